@@ -0,0 +1,180 @@
+package fontpic
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/rusq/fontpic/charset"
+)
+
+// banner.go contains the generator of the text banners, made of characters,
+// and the exporter to the FIGlet font format.
+
+// BannerOptions are the options of the banner.
+type BannerOptions struct {
+	// On and Off are the characters for the set and unset pixels.  If zero,
+	// '#' and ' ' are used.
+	On, Off rune
+	// HalfBlock packs two rows of pixels in one line of text with the upper
+	// and lower half blocks.  On is ignored.
+	HalfBlock bool
+}
+
+var (
+	// BannerASCII draws the pixels with the hash signs.
+	BannerASCII = BannerOptions{On: '#', Off: ' '}
+	// BannerBlock draws the pixels with the full blocks.
+	BannerBlock = BannerOptions{On: '█', Off: ' '}
+	// BannerHalfBlock draws two rows of pixels per line with the half blocks.
+	BannerHalfBlock = BannerOptions{Off: ' ', HalfBlock: true}
+)
+
+func (o *BannerOptions) ensure() {
+	if o.On == 0 {
+		o.On = '#'
+	}
+	if o.Off == 0 {
+		o.Off = ' '
+	}
+}
+
+// lines converts the w x h pixels to the lines of text.
+func (o BannerOptions) lines(w, h int, pixel func(x, y int) bool) []string {
+	o.ensure()
+	if !o.HalfBlock {
+		ret := make([]string, h)
+		for y := range h {
+			var sb strings.Builder
+			for x := range w {
+				sb.WriteRune(pick(pixel(x, y), o.On, o.Off))
+			}
+			ret[y] = sb.String()
+		}
+		return ret
+	}
+	ret := make([]string, 0, (h+1)/2)
+	for y := 0; y < h; y += 2 {
+		var sb strings.Builder
+		for x := range w {
+			top, bottom := pixel(x, y), y+1 < h && pixel(x, y+1)
+			switch {
+			case top && bottom:
+				sb.WriteRune('█')
+			case top:
+				sb.WriteRune('▀')
+			case bottom:
+				sb.WriteRune('▄')
+			default:
+				sb.WriteRune(o.Off)
+			}
+		}
+		ret = append(ret, sb.String())
+	}
+	return ret
+}
+
+// Banner renders the text to the multi-line banner.  Same as with RenderText,
+// newlines separate the lines and tabs are replaced with spaces.  The text is
+// in the font character set, use [charset.Charset.Translate] to convert the
+// UTF-8 strings.
+func (f *FNT) Banner(text []byte, opts BannerOptions) string {
+	c := &Canvas{Font: f}
+	c.ensure()
+	lines := c.toGlyphs(textLines(text))
+	c.calcSize(lines)
+	mask := image.NewAlpha(image.Rect(0, 0, c.Width, c.Height))
+	glyphs, _ := c.layout(lines, image.Point{})
+	for _, g := range glyphs {
+		g.font.drawChar(mask, g.at, g.ch, color.Opaque, nil)
+	}
+	rows := opts.lines(c.Width, c.Height, func(x, y int) bool {
+		return mask.AlphaAt(x, y).A != 0
+	})
+	return strings.Join(rows, "\n") + "\n"
+}
+
+// Banner renders the text to the multi-line banner.  See [FNT.Banner].
+func (f *ImageFont) Banner(text []byte, opts BannerOptions) string {
+	return f.ToFnt().Banner(text, opts)
+}
+
+// flfGerman are the German characters, that are required in the FIGlet font
+// after the ASCII characters.
+var flfGerman = []rune{'Ä', 'Ö', 'Ü', 'ä', 'ö', 'ü', 'ß'}
+
+// WriteFLF writes the font in the FIGlet font format (.flf) to w.  The
+// characters are drawn as with [FNT.Banner], and the layout is the full
+// width, as the pixel fonts do not smush.  The characters missing in the font
+// are written as empty.  The characters beyond ASCII are written with the
+// Unicode code tags, using the font charset.  The hard blank is '$' and the
+// end mark is '@', so they should not be used as On and Off characters.
+func (f *FNT) WriteFLF(w io.Writer, opts BannerOptions) error {
+	cs, _ := charset.ByName(f.Charset)
+	var (
+		runes  = f.runes(cs)
+		height = pick(opts.HalfBlock, (f.Height+1)/2, f.Height)
+		// the baseline is the line with the bottom of the capital letters.
+		baseline = pick(opts.HalfBlock, (f.Height-f.descent()+1)/2, f.Height-f.descent())
+		maxLen   int
+	)
+	for ch := range CharsetSz {
+		maxLen = max(maxLen, f.Advance(byte(ch)))
+	}
+	// the required characters, and the rest of the font code tagged.
+	required := make([]rune, 0, 95+len(flfGerman))
+	for r := ' '; r <= '~'; r++ {
+		required = append(required, r)
+	}
+	required = append(required, flfGerman...)
+	var tagged []rune
+	for r := range runes {
+		if r > '~' && !unicode.IsControl(r) && !slices.Contains(required, r) {
+			tagged = append(tagged, r)
+		}
+	}
+	slices.Sort(tagged)
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "flf2a$ %d %d %d -1 1 0 0 %d\n", height, baseline, maxLen+2, len(tagged))
+	fmt.Fprintf(bw, "Converted by fontpic from the %dx%d bitmap font.\n", f.Width, f.Height)
+	for _, r := range required {
+		ch, ok := runes[r]
+		f.writeFLFChar(bw, ch, ok, height, opts)
+	}
+	for _, r := range tagged {
+		fmt.Fprintf(bw, "0x%04X\n", r)
+		f.writeFLFChar(bw, runes[r], true, height, opts)
+	}
+	return bw.Flush()
+}
+
+// writeFLFChar writes the lines of the character ch, terminated with the end
+// marks.  If the character is not present, the empty character is written.
+func (f *FNT) writeFLFChar(w *bufio.Writer, ch int, present bool, height int, opts BannerOptions) {
+	rows := make([]string, height)
+	if present {
+		left, adv := f.hmetrics(byte(ch))
+		rows = opts.lines(adv, f.Height, func(x, y int) bool {
+			return f.Pixel(byte(ch), left+x, y)
+		})
+	}
+	for i, row := range rows {
+		w.WriteString(row)
+		if i == len(rows)-1 {
+			w.WriteString("@@\n")
+		} else {
+			w.WriteString("@\n")
+		}
+	}
+}
+
+// WriteFLF writes the font in the FIGlet font format.  See [FNT.WriteFLF].
+func (f *ImageFont) WriteFLF(w io.Writer, opts BannerOptions) error {
+	return f.ToFnt().WriteFLF(w, opts)
+}
@@ -0,0 +1,116 @@
+package fontpic
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestFNT_Banner(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		opts BannerOptions
+		want string
+	}{
+		{
+			"ascii",
+			"II",
+			BannerASCII,
+			" #   #  \n #   #  \n #   #  \n #   #  \n",
+		},
+		{
+			"zero options",
+			"I",
+			BannerOptions{},
+			" #  \n #  \n #  \n #  \n",
+		},
+		{
+			"custom",
+			"I",
+			BannerOptions{On: 'X', Off: '.'},
+			".X..\n.X..\n.X..\n.X..\n",
+		},
+		{
+			"half block",
+			"I I",
+			BannerHalfBlock,
+			" █       █  \n █       █  \n",
+		},
+		{
+			"lines",
+			"I\nI",
+			BannerBlock,
+			" █  \n █  \n █  \n █  \n █  \n █  \n █  \n █  \n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := testFont().Banner([]byte(tt.text), tt.opts); got != tt.want {
+				t.Errorf("Banner() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFNT_WriteFLF(t *testing.T) {
+	tests := []struct {
+		name       string
+		charset    string
+		opts       BannerOptions
+		wantHeader string
+		wantI      []string
+		wantTagged int
+	}{
+		{
+			"latin-1",
+			"",
+			BannerASCII,
+			"flf2a$ 4 3 6 -1 1 0 0 89",
+			[]string{" #  @", " #  @", " #  @", " #  @@"},
+			// 0xa0-0xff without the 7 German characters.
+			89,
+		},
+		{
+			"cp866 half block",
+			"866",
+			BannerHalfBlock,
+			"flf2a$ 2 2 6 -1 1 0 0 128",
+			[]string{" █  @", " █  @@"},
+			// the characters above 0x7f.
+			128,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := testFont()
+			f.Charset = tt.charset
+			var buf bytes.Buffer
+			if err := f.WriteFLF(&buf, tt.opts); err != nil {
+				t.Fatalf("WriteFLF() error = %v", err)
+			}
+			lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+			if lines[0] != tt.wantHeader {
+				t.Errorf("header = %q, want %q", lines[0], tt.wantHeader)
+			}
+			height := len(tt.wantI)
+			// header, comment, then the characters from the space.
+			start := 2 + int('I'-' ')*height
+			if got := lines[start : start+height]; strings.Join(got, "\n") != strings.Join(tt.wantI, "\n") {
+				t.Errorf("'I' = %q, want %q", got, tt.wantI)
+			}
+			var tags int
+			for _, l := range lines[2+(95+len(flfGerman))*height:] {
+				if strings.HasPrefix(l, "0x") {
+					tags++
+				}
+			}
+			if tags != tt.wantTagged {
+				t.Errorf("code tagged = %d, want %d", tags, tt.wantTagged)
+			}
+			if want := 2 + (95+len(flfGerman)+tt.wantTagged)*height + tt.wantTagged; len(lines) != want {
+				t.Errorf("lines = %d, want %d", len(lines), want)
+			}
+		})
+	}
+}
@@ -1,9 +1,28 @@
 package charset
 
-import "unicode/utf8"
+import (
+	"strings"
+	"unicode/utf8"
+)
 
 type Charset string
 
+// charsets maps the code page names, as used in the FNT.Charset field, to the
+// character sets.
+var charsets = map[string]Charset{
+	"866":    CP866,
+	"cp866":  CP866,
+	"dos866": CP866,
+	"ibm866": CP866,
+}
+
+// ByName returns the character set for the code page name, i.e. "866" or
+// "cp866".  The name is case insensitive.
+func ByName(name string) (Charset, bool) {
+	cs, ok := charsets[strings.ToLower(name)]
+	return cs, ok
+}
+
 func (c Charset) Translate(s string) []byte {
 	b := make([]byte, utf8.RuneCountInString(s))
 	for i, r := range []rune(s) {
@@ -12,14 +31,39 @@ func (c Charset) Translate(s string) []byte {
 	return b
 }
 
+// TranslateRune returns the code of the rune r in the character set.  If the
+// rune is not in the character set, it returns '?'.
 func (c Charset) TranslateRune(r rune) byte {
+	if b, ok := c.Lookup(r); ok {
+		return b
+	}
+	return '?'
+}
+
+// Lookup returns the code of the rune r in the character set, and true if the
+// rune is in the character set.
+func (c Charset) Lookup(r rune) (byte, bool) {
 	if r < 0x80 {
-		return byte(r)
+		return byte(r), true
 	}
 	for i, rr := range []rune(c) {
 		if rr == r {
-			return byte(i) + 0x80
+			return byte(i) + 0x80, true
 		}
 	}
-	return c[r]
+	return 0, false
+}
+
+// Rune returns the rune for the character code b.  Codes below 0x80 are
+// treated as ASCII.  If the code is not defined in the character set, it
+// returns utf8.RuneError.
+func (c Charset) Rune(b byte) rune {
+	if b < 0x80 {
+		return rune(b)
+	}
+	rr := []rune(c)
+	if int(b-0x80) >= len(rr) {
+		return utf8.RuneError
+	}
+	return rr[b-0x80]
 }
@@ -68,3 +68,51 @@ func TestCharset_Translate(t *testing.T) {
 		})
 	}
 }
+
+func TestCharset_Rune(t *testing.T) {
+	tests := []struct {
+		name string
+		c    Charset
+		b    byte
+		want rune
+	}{
+		{"ASCII", CP866, 'A', 'A'},
+		{"Cyrillic A", CP866, 0x80, 'А'},
+		{"Cyrillic p", CP866, 0xE0, 'р'},
+		{"last", CP866, 0xFF, '\u00a0'},
+		{"empty charset", Charset(""), 0x80, '\ufffd'},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.c.Rune(tt.b); got != tt.want {
+				t.Errorf("Charset.Rune() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCharset_TranslateRune_unknown(t *testing.T) {
+	if got := CP866.TranslateRune('€'); got != '?' {
+		t.Errorf("Charset.TranslateRune() = %#x, want '?'", got)
+	}
+}
+
+func TestByName(t *testing.T) {
+	tests := []struct {
+		name   string
+		want   Charset
+		wantOk bool
+	}{
+		{"866", CP866, true},
+		{"CP866", CP866, true},
+		{"437", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ByName(tt.name)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("ByName() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
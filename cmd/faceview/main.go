@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"image"
 	"image/draw"
@@ -16,39 +17,62 @@ import (
 	"github.com/rusq/fontpic"
 )
 
-var fonts = []*basicfont.Face{
-	basicfont.Face7x13,
-	fontpic.Face8x16,
-	fontpic.Face8x14,
-	fontpic.Face8x8,
-	fontpic.Face4x4,
-	fontpic.Face4x4Bold,
-	fontpic.Face4x4Italic,
-	fontpic.Face4x5,
-	fontpic.Face6x5,
-	fontpic.FaceRobotron,
+// face returns the loader of the face, that can't fail.
+func face(f *basicfont.Face) func() (*basicfont.Face, error) {
+	return func() (*basicfont.Face, error) { return f, nil }
 }
 
+var fonts = []func() (*basicfont.Face, error){
+	face(basicfont.Face7x13),
+	face(fontpic.Face8x16),
+	face(fontpic.Face8x14),
+	face(fontpic.Face8x8),
+	fontpic.LoadFace4x4,
+	fontpic.LoadFace4x4Bold,
+	fontpic.LoadFace4x4Italic,
+	fontpic.LoadFace4x5,
+	fontpic.LoadFace6x5,
+	face(fontpic.FaceRobotron),
+}
+
+var term = flag.String("term", "", "print images to the terminal instead of files: halfblock, braille or sixel")
+
 func main() {
+	flag.Parse()
 	for i := range fonts {
-		f, err := os.Create(fmt.Sprintf("%d.png", i))
+		face, err := fonts[i]()
 		if err != nil {
 			log.Fatal(err)
 		}
-		if err := png.Encode(f, fonts[i].Mask); err != nil {
-			log.Fatal(err)
-		}
-		f.Close()
-		sample(fmt.Sprintf("sample%d.png", i), fonts[i])
+		writeImage(fmt.Sprintf("%d.png", i), face.Mask)
+		sample(fmt.Sprintf("sample%d.png", i), face)
 	}
 }
 
-func sample(filename string, face *basicfont.Face) {
+// writeImage writes the image to the png file, or to the terminal, if the
+// terminal mode is set.
+func writeImage(filename string, img image.Image) {
+	if *term != "" {
+		mode, err := fontpic.ParseTermMode(*term)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := fontpic.WriteTerm(os.Stdout, img, mode); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
 	f, err := os.Create(filename)
 	if err != nil {
 		log.Fatal(err)
 	}
 	defer f.Close()
+	if err := png.Encode(f, img); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func sample(filename string, face *basicfont.Face) {
 
 	text := " !\"#$%&'()*+,-./0123456789:;<=>?\n" +
 		"@ABCDEFGHIJKLMNOPQRSTUVWXYZ[\\]^_`abcdefghijklmnopqrstuvwxyz{|}~\n" +
@@ -75,7 +99,5 @@ func sample(filename string, face *basicfont.Face) {
 		d.Dot.X = fixed.I(0)             // Reset X position to the start of the line
 		d.Dot.Y += face.Metrics().Height // + face.Metrics().Ascent
 	}
-	if err := png.Encode(f, img); err != nil {
-		log.Fatal(err)
-	}
+	writeImage(filename, img)
 }
@@ -0,0 +1,81 @@
+// Command fontdiff compares two FNT fonts glyph by glyph.  It lists the
+// characters that differ with the number of added and removed pixels, and
+// optionally renders the visual diff sheet.
+//
+// Usage:
+//
+//	fontdiff [-w width] [-w2 width] [-o diff.png] a.fnt b.fnt
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image/png"
+	"log"
+	"os"
+
+	"github.com/rusq/fontpic"
+)
+
+var (
+	width   = flag.Int("w", 8, "font width")
+	width2  = flag.Int("w2", 0, "width of the second font, if different")
+	output  = flag.String("o", "", "diff sheet png file")
+	perLine = flag.Int("n", 16, "characters per line in the diff sheet")
+)
+
+func main() {
+	flag.Parse()
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *perLine < 1 {
+		log.Fatal("-n must be positive")
+	}
+	if *width2 == 0 {
+		*width2 = *width
+	}
+	a, err := fontpic.LoadFnt(flag.Arg(0), *width)
+	if err != nil {
+		log.Fatal(err)
+	}
+	b, err := fontpic.LoadFnt(flag.Arg(1), *width2)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if a.Width != b.Width || a.Height != b.Height {
+		fmt.Printf("size: %dx%d -> %dx%d\n", a.Width, a.Height, b.Width, b.Height)
+	}
+	diffs := fontpic.Diff(a, b)
+	for _, d := range diffs {
+		fmt.Printf("0x%02x %s\t+%d -%d\n", d.Char, printable(d.Char), d.Added, d.Removed)
+	}
+	fmt.Printf("%d characters differ\n", len(diffs))
+
+	if *output != "" {
+		if err := writeSheet(*output, a, b); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if len(diffs) > 0 {
+		os.Exit(1)
+	}
+}
+
+func writeSheet(filename string, a, b *fontpic.FNT) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return png.Encode(f, fontpic.DiffSheet(a, b, *perLine))
+}
+
+// printable returns the quoted ASCII character, or an empty string.
+func printable(ch byte) string {
+	if ch < ' ' || ch > '~' {
+		return ""
+	}
+	return fmt.Sprintf("%q", ch)
+}
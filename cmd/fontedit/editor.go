@@ -0,0 +1,254 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"io"
+	"slices"
+	"unicode"
+	"unicode/utf8"
+)
+
+// editor is the state of the font editor.
+type editor struct {
+	doc *document
+	// ch is the edited character and cur is the cursor in the glyph.
+	ch  byte
+	cur image.Point
+	// clip is the copied glyph, or nil.
+	clip     []byte
+	modified bool
+	// text is the preview text.  While input is true, the keys edit the
+	// buffer instead of the glyph.
+	text   string
+	input  bool
+	buffer []rune
+	// status is the message shown at the bottom of the screen.
+	status   string
+	quitting bool
+	done     bool
+}
+
+func newEditor(doc *document, text string) *editor {
+	ch := byte('A')
+	if ch < doc.first || ch > doc.last {
+		ch = doc.first
+	}
+	return &editor{doc: doc, ch: ch, text: text}
+}
+
+// run runs the editor on the terminal until the user quits.
+func (e *editor) run(in io.Reader, out io.Writer) error {
+	restore, err := rawMode()
+	if err != nil {
+		return err
+	}
+	defer restore()
+	bw := bufio.NewWriter(out)
+	bw.WriteString("\x1b[?1049h\x1b[?25l") // alternate screen, hide cursor
+	defer func() {
+		bw.WriteString("\x1b[?25h\x1b[?1049l")
+		bw.Flush()
+	}()
+
+	buf := make([]byte, 64)
+	for !e.done {
+		e.draw(bw)
+		if err := bw.Flush(); err != nil {
+			return err
+		}
+		n, err := in.Read(buf)
+		if err != nil {
+			return err
+		}
+		for _, k := range parseKeys(buf[:n]) {
+			e.handle(k)
+		}
+	}
+	return nil
+}
+
+// Special keys, the other keys are the strings of the runes.
+const (
+	keyUp        = "up"
+	keyDown      = "down"
+	keyLeft      = "left"
+	keyRight     = "right"
+	keyPgUp      = "pgup"
+	keyPgDn      = "pgdn"
+	keyEnter     = "enter"
+	keyEsc       = "esc"
+	keyBackspace = "backspace"
+	keyCtrlC     = "ctrl-c"
+)
+
+// parseKeys splits the terminal input into keys.
+func parseKeys(b []byte) []string {
+	var keys []string
+	for len(b) > 0 {
+		if b[0] == 0x1b && len(b) >= 3 && b[1] == '[' {
+			if k, ok := map[byte]string{'A': keyUp, 'B': keyDown, 'C': keyRight, 'D': keyLeft}[b[2]]; ok {
+				keys, b = append(keys, k), b[3:]
+				continue
+			}
+			if len(b) >= 4 && b[3] == '~' && (b[2] == '5' || b[2] == '6') {
+				k := keyPgUp
+				if b[2] == '6' {
+					k = keyPgDn
+				}
+				keys, b = append(keys, k), b[4:]
+				continue
+			}
+		}
+		switch b[0] {
+		case 0x1b:
+			keys, b = append(keys, keyEsc), b[1:]
+		case '\r', '\n':
+			keys, b = append(keys, keyEnter), b[1:]
+		case 0x7f, 0x08:
+			keys, b = append(keys, keyBackspace), b[1:]
+		case 0x03:
+			keys, b = append(keys, keyCtrlC), b[1:]
+		default:
+			r, size := utf8.DecodeRune(b)
+			keys, b = append(keys, string(r)), b[size:]
+		}
+	}
+	return keys
+}
+
+// handle processes the key.
+func (e *editor) handle(k string) {
+	if k == keyCtrlC {
+		e.done = true
+		return
+	}
+	if e.input {
+		e.handleInput(k)
+		return
+	}
+	quitting := e.quitting
+	e.quitting = false
+	e.status = ""
+	size := e.doc.size
+	switch k {
+	case keyUp, "k":
+		e.cur.Y = (e.cur.Y + size.Y - 1) % size.Y
+	case keyDown, "j":
+		e.cur.Y = (e.cur.Y + 1) % size.Y
+	case keyLeft, "h":
+		e.cur.X = (e.cur.X + size.X - 1) % size.X
+	case keyRight, "l":
+		e.cur.X = (e.cur.X + 1) % size.X
+	case " ":
+		f := e.doc.font
+		f.SetPixel(e.ch, e.cur.X, e.cur.Y, !f.Pixel(e.ch, e.cur.X, e.cur.Y))
+		e.modified = true
+	case "[":
+		e.selectChar(int(e.ch) - 1)
+	case "]":
+		e.selectChar(int(e.ch) + 1)
+	case keyPgUp:
+		e.selectChar(int(e.ch) - 16)
+	case keyPgDn:
+		e.selectChar(int(e.ch) + 16)
+	case "c":
+		e.clip = slices.Clone(e.doc.font.Chars[e.ch])
+		e.status = fmt.Sprintf("copied 0x%02x", e.ch)
+	case "v":
+		if e.clip == nil {
+			e.status = "nothing to paste"
+			break
+		}
+		copy(e.doc.font.Chars[e.ch], e.clip)
+		e.modified = true
+	case "w":
+		e.shift(0, -1)
+	case "a":
+		e.shift(-1, 0)
+	case "s":
+		e.shift(0, 1)
+	case "d":
+		e.shift(1, 0)
+	case "f":
+		e.transform(func(x, y int, px func(x, y int) bool) bool { return px(size.X-1-x, y) })
+	case "F":
+		e.transform(func(x, y int, px func(x, y int) bool) bool { return px(x, size.Y-1-y) })
+	case "i":
+		e.transform(func(x, y int, px func(x, y int) bool) bool { return !px(x, y) })
+	case "t":
+		e.input, e.buffer = true, []rune(e.text)
+	case "S":
+		if err := e.doc.save(); err != nil {
+			e.status = "save: " + err.Error()
+			break
+		}
+		e.modified = false
+		e.status = "saved " + e.doc.filename
+	case "q":
+		if e.modified && !quitting {
+			e.quitting = true
+			e.status = "unsaved changes, press q again to quit"
+			break
+		}
+		e.done = true
+	}
+}
+
+// handleInput edits the preview text.
+func (e *editor) handleInput(k string) {
+	switch k {
+	case keyEnter:
+		e.text, e.input = string(e.buffer), false
+	case keyEsc:
+		e.input = false
+	case keyBackspace:
+		if len(e.buffer) > 0 {
+			e.buffer = e.buffer[:len(e.buffer)-1]
+		}
+	default:
+		if r := []rune(k); len(r) == 1 && unicode.IsPrint(r[0]) {
+			e.buffer = append(e.buffer, r[0])
+		}
+	}
+}
+
+// selectChar selects the character ch, clamped to the editable range.
+func (e *editor) selectChar(ch int) {
+	e.ch = byte(min(max(ch, int(e.doc.first)), int(e.doc.last)))
+}
+
+// shift moves the glyph by dx, dy, the pixels shifted out of the cell wrap
+// around.
+func (e *editor) shift(dx, dy int) {
+	size := e.doc.size
+	e.transform(func(x, y int, px func(x, y int) bool) bool {
+		return px((x-dx+size.X)%size.X, (y-dy+size.Y)%size.Y)
+	})
+}
+
+// transform replaces every pixel in the editable area of the glyph with the
+// result of fn, that is given the pixel function of the original glyph.
+func (e *editor) transform(fn func(x, y int, px func(x, y int) bool) bool) {
+	var (
+		f    = e.doc.font
+		orig = slices.Clone(f.Chars[e.ch])
+		size = e.doc.size
+	)
+	px := func(x, y int) bool {
+		return f.Pixel(e.ch, x, y)
+	}
+	next := make([]bool, size.X*size.Y)
+	for y := range size.Y {
+		for x := range size.X {
+			next[y*size.X+x] = fn(x, y, px)
+		}
+	}
+	for y := range size.Y {
+		for x := range size.X {
+			f.SetPixel(e.ch, x, y, next[y*size.X+x])
+		}
+	}
+	e.modified = e.modified || !slices.Equal(orig, f.Chars[e.ch])
+}
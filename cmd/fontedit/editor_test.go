@@ -0,0 +1,190 @@
+package main
+
+import (
+	"image"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/rusq/fontpic"
+)
+
+func TestParseKeys(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{"runes", "hjЖ ", []string{"h", "j", "Ж", " "}},
+		{"arrows", "\x1b[A\x1b[B\x1b[C\x1b[D", []string{keyUp, keyDown, keyRight, keyLeft}},
+		{"pages", "\x1b[5~\x1b[6~", []string{keyPgUp, keyPgDn}},
+		{"control", "\r\n\x7f\x08\x03", []string{keyEnter, keyEnter, keyBackspace, keyBackspace, keyCtrlC}},
+		{"lone escape", "\x1bq", []string{keyEsc, "q"}},
+		{"unknown sequence", "\x1b[Z", []string{keyEsc, "[", "Z"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseKeys([]byte(tt.input)); !slices.Equal(got, tt.want) {
+				t.Errorf("parseKeys() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// testDoc returns the document with the blank 4x4 font.
+func testDoc(t *testing.T) *document {
+	t.Helper()
+	f, err := fontpic.ToFnt(make([]byte, fontpic.CharsetSz*4), 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &document{font: f, size: image.Pt(4, 4), first: 0, last: fontpic.CharsetSz - 1}
+}
+
+// glyph returns the character bitmap as the rows of '#' and '.'.
+func glyph(f *fontpic.FNT, ch byte) string {
+	var rows []string
+	for y := range f.Height {
+		var sb strings.Builder
+		for x := range f.Width {
+			if f.Pixel(ch, x, y) {
+				sb.WriteByte('#')
+			} else {
+				sb.WriteByte('.')
+			}
+		}
+		rows = append(rows, sb.String())
+	}
+	return strings.Join(rows, "|")
+}
+
+func TestEditor_handle(t *testing.T) {
+	tests := []struct {
+		name         string
+		set          []image.Point // pixels of 'A' set before the keys.
+		keys         []string
+		wantCh       byte
+		want         string // glyph of wantCh.
+		wantModified bool
+	}{
+		{"toggle", nil, []string{" "}, 'A', "#...|....|....|....", true},
+		{"toggle twice", nil, []string{" ", " "}, 'A', "....|....|....|....", true},
+		{"move and toggle", nil, []string{"l", keyDown, " "}, 'A', "....|.#..|....|....", true},
+		{"cursor wraps", nil, []string{keyLeft, keyUp, " "}, 'A', "....|....|....|...#", true},
+		{"shift right wraps", []image.Point{{3, 0}}, []string{"d"}, 'A', "#...|....|....|....", true},
+		{"shift up wraps", []image.Point{{1, 0}}, []string{"w"}, 'A', "....|....|....|.#..", true},
+		{"flip horizontally", []image.Point{{0, 1}}, []string{"f"}, 'A', "....|...#|....|....", true},
+		{"flip vertically", []image.Point{{0, 1}}, []string{"F"}, 'A', "....|....|#...|....", true},
+		{"invert", []image.Point{{0, 0}}, []string{"i"}, 'A', ".###|####|####|####", true},
+		{"flip blank is not a change", nil, []string{"f"}, 'A', "....|....|....|....", false},
+		{"copy and paste", []image.Point{{2, 2}}, []string{"c", "]", "v"}, 'B', "....|....|..#.|....", true},
+		{"paste without copy", nil, []string{"]", "v"}, 'B', "....|....|....|....", false},
+		{"page down", nil, []string{keyPgDn, " "}, 'A' + 16, "#...|....|....|....", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newEditor(testDoc(t), "")
+			for _, p := range tt.set {
+				e.doc.font.SetPixel('A', p.X, p.Y, true)
+			}
+			for _, k := range tt.keys {
+				e.handle(k)
+			}
+			if e.ch != tt.wantCh {
+				t.Fatalf("ch = %q, want %q", e.ch, tt.wantCh)
+			}
+			if got := glyph(e.doc.font, tt.wantCh); got != tt.want {
+				t.Errorf("glyph = %s, want %s", got, tt.want)
+			}
+			if e.modified != tt.wantModified {
+				t.Errorf("modified = %v, want %v", e.modified, tt.wantModified)
+			}
+		})
+	}
+}
+
+func TestEditor_quit(t *testing.T) {
+	e := newEditor(testDoc(t), "")
+	e.handle(" ")
+	e.handle("q")
+	if e.done {
+		t.Fatal("quit with unsaved changes on the first q")
+	}
+	e.handle("q")
+	if !e.done {
+		t.Error("second q does not quit")
+	}
+}
+
+func TestEditor_text(t *testing.T) {
+	e := newEditor(testDoc(t), "ab")
+	for _, k := range []string{"t", keyBackspace, "c", "Ж", keyEnter} {
+		e.handle(k)
+	}
+	if e.text != "acЖ" || e.input {
+		t.Errorf("text = %q, input = %v, want \"acЖ\", false", e.text, e.input)
+	}
+	if e.clip != nil {
+		t.Error("the keys of the text input are handled as the commands")
+	}
+}
+
+// copyFile copies the file to the directory and returns the new name.
+func copyFile(t *testing.T, src, dir string) string {
+	t.Helper()
+	data, err := os.ReadFile(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dst := filepath.Join(dir, filepath.Base(src))
+	if err := os.WriteFile(dst, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return dst
+}
+
+func TestDocument_save(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		ch   byte
+	}{
+		{"fnt", "../../fnt/08X08.FNT", 'A'},
+		{"png", "../../imgfonts/microfont.png", 'A'},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := copyFile(t, tt.src, t.TempDir())
+			doc, err := open(filename)
+			if err != nil {
+				t.Fatal(err)
+			}
+			orig := glyph(doc.font, tt.ch)
+			other := glyph(doc.font, tt.ch+1)
+
+			e := newEditor(doc, "")
+			e.handle(" ")
+			e.handle("S")
+			if e.modified {
+				t.Fatalf("modified after save, status %q", e.status)
+			}
+			want := glyph(doc.font, tt.ch)
+			if want == orig {
+				t.Fatal("glyph is not changed")
+			}
+
+			got, err := open(filename)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if g := glyph(got.font, tt.ch); g != want {
+				t.Errorf("reloaded glyph = %s, want %s", g, want)
+			}
+			if g := glyph(got.font, tt.ch+1); g != other {
+				t.Errorf("unchanged glyph = %s, want %s", g, other)
+			}
+		})
+	}
+}
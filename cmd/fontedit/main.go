@@ -0,0 +1,145 @@
+// Command fontedit is the terminal editor of the bitmap fonts.  It opens the
+// FNT font or the PNG image font, shows the enlarged glyph and the character
+// table, and saves the font back in the original format.
+//
+// Usage:
+//
+//	fontedit [-w width] [-charset 866] font.fnt
+//	fontedit [-grid 4x4] [-pad 1] [-start 32] [-end 127] font.png
+//
+// Keys:
+//
+//	arrows, hjkl  move the cursor        space  toggle the pixel
+//	[ ]           previous/next char     PgUp PgDn  16 characters back/forward
+//	c v           copy/paste the glyph   wasd   shift the glyph
+//	f F           flip horizontally/vertically
+//	i             invert the glyph       t      edit the preview text
+//	S             save                   q      quit
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	"image/color"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rusq/fontpic"
+)
+
+var (
+	fontWidth = flag.Int("w", 8, "FNT font width")
+	csName    = flag.String("charset", "", "FNT font charset, i.e. 866")
+	grid      = flag.String("grid", "4x4", "image font character size, WxH")
+	padding   = flag.Int("pad", 1, "image font grid padding")
+	charStart = flag.Uint("start", 32, "image font first character code")
+	charEnd   = flag.Uint("end", 127, "image font last character code")
+	preview   = flag.String("text", "The quick brown fox jumps over the lazy dog", "preview text")
+)
+
+func main() {
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	doc, err := open(flag.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	ed := newEditor(doc, *preview)
+	if err := ed.run(os.Stdin, os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// document is the font being edited.  The glyphs are always edited as FNT,
+// the image font is updated from it on save.
+type document struct {
+	filename string
+	font     *fontpic.FNT
+	// image is the original image font, or nil for FNT.
+	image *fontpic.ImageFont
+	// size is the editable area of the glyph, and first and last are the
+	// editable characters.
+	size        image.Point
+	first, last byte
+}
+
+func open(filename string) (*document, error) {
+	if !strings.EqualFold(filepath.Ext(filename), ".png") {
+		f, err := fontpic.LoadFnt(filename, *fontWidth)
+		if err != nil {
+			return nil, err
+		}
+		f.Charset = *csName
+		return &document{
+			filename: filename,
+			font:     f,
+			size:     image.Pt(f.Width, f.Height),
+			first:    0,
+			last:     fontpic.CharsetSz - 1,
+		}, nil
+	}
+
+	var w, h int
+	if _, err := fmt.Sscanf(*grid, "%dx%d", &w, &h); err != nil || w < 1 || h < 1 {
+		return nil, fmt.Errorf("invalid grid size: %q", *grid)
+	}
+	if *charStart > *charEnd || *charEnd >= fontpic.CharsetSz {
+		return nil, fmt.Errorf("invalid character range: %d-%d", *charStart, *charEnd)
+	}
+	imf := &fontpic.ImageFont{
+		Name:        strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename)),
+		Width:       w,
+		GridSize:    image.Pt(w, h),
+		GridPadding: *padding,
+		CharStart:   byte(*charStart),
+		CharEnd:     byte(*charEnd),
+		Transparent: color.Transparent,
+	}
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if err := imf.Load(f); err != nil {
+		return nil, err
+	}
+	return &document{
+		filename: filename,
+		font:     imf.ToFnt(),
+		image:    imf,
+		size:     imf.GridSize,
+		first:    imf.CharStart,
+		last:     imf.CharEnd,
+	}, nil
+}
+
+// save writes the font back to the file in the original format.
+func (d *document) save() error {
+	if d.image == nil {
+		return os.WriteFile(d.filename, d.font.Bytes(), 0o644)
+	}
+	for ch := int(d.first); ch <= int(d.last); ch++ {
+		for y := range d.size.Y {
+			for x := range d.size.X {
+				if err := d.image.SetPixel(byte(ch), x, y, d.font.Pixel(byte(ch), x, y)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	f, err := os.Create(d.filename)
+	if err != nil {
+		return err
+	}
+	if err := d.image.WritePNG(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"strings"
+	"unicode"
+
+	"github.com/rusq/fontpic"
+	"github.com/rusq/fontpic/charset"
+)
+
+// screen.go draws the editor screen with the ANSI escape sequences.
+
+const (
+	sgrReset   = "\x1b[0m"
+	sgrReverse = "\x1b[7m"
+	sgrDim     = "\x1b[2m"
+)
+
+// draw redraws the whole screen.
+func (e *editor) draw(w *bufio.Writer) {
+	cols, rows := termSize()
+	w.WriteString("\x1b[H\x1b[2J")
+
+	var (
+		f  = e.doc.font
+		cs = e.charset()
+	)
+	modified := ""
+	if e.modified {
+		modified = " [modified]"
+	}
+	fmt.Fprintf(w, "%s  %dx%d  char 0x%02x %s%s\n\n", e.doc.filename, e.doc.size.X, e.doc.size.Y, e.ch, printable(cs, e.ch), modified)
+
+	glyph, table := e.glyphLines(), e.tableLines(cs)
+	glyphWidth := 2*e.doc.size.X + 3
+	for i := range max(len(glyph), len(table)) {
+		var line string
+		if i < len(glyph) {
+			line = glyph[i]
+		}
+		w.WriteString(line)
+		if i < len(table) {
+			// the glyph lines contain the escape sequences, so the padding is
+			// calculated from the cell width.
+			pad := glyphWidth
+			if i < len(glyph) {
+				pad -= 2 * e.doc.size.X
+			}
+			w.WriteString(strings.Repeat(" ", pad))
+			w.WriteString(table[i])
+		}
+		w.WriteString("\n")
+	}
+	w.WriteString("\n")
+
+	if text := cs.Translate(e.text); len(text) > 0 {
+		img := fontpic.NewCanvas(f).
+			WithForeground(color.White).
+			WithBackground(color.Black).
+			RenderText(text).
+			Image()
+		// the preview is cropped to the terminal width and the half of the
+		// rows left.
+		maxRows := max(rows-len(glyph)-len(table), 2)
+		crop := img.Bounds().Intersect(image.Rect(0, 0, cols, 2*maxRows))
+		if sub, ok := img.(interface {
+			SubImage(image.Rectangle) image.Image
+		}); ok {
+			fontpic.WriteTerm(w, sub.SubImage(crop), fontpic.TermHalfBlock)
+		}
+	}
+	w.WriteString("\n")
+
+	if e.input {
+		fmt.Fprintf(w, "preview text: %s%s %s\n", string(e.buffer), sgrReverse, sgrReset)
+		w.WriteString(sgrDim + "enter accept  esc cancel" + sgrReset)
+		return
+	}
+	fmt.Fprintf(w, "%s\n", e.status)
+	w.WriteString(sgrDim + "arrows move  space toggle  [] char  c/v copy/paste  wasd shift  f/F flip  i invert  t text  S save  q quit" + sgrReset)
+}
+
+// charset returns the character set of the font.
+func (e *editor) charset() charset.Charset {
+	cs, _ := charset.ByName(e.doc.font.Charset)
+	return cs
+}
+
+// glyphLines returns the enlarged glyph, two columns per pixel, with the
+// cursor in reverse video.
+func (e *editor) glyphLines() []string {
+	var lines []string
+	for y := range e.doc.size.Y {
+		var sb strings.Builder
+		for x := range e.doc.size.X {
+			cell := sgrDim + "··" + sgrReset
+			if e.doc.font.Pixel(e.ch, x, y) {
+				cell = "██"
+			}
+			if x == e.cur.X && y == e.cur.Y {
+				cell = sgrReverse + cell + sgrReset
+			}
+			sb.WriteString(cell)
+		}
+		lines = append(lines, sb.String())
+	}
+	return lines
+}
+
+// tableLines returns the 16x16 table of characters with the current one in
+// reverse video.  The characters outside of the editable range are blank.
+func (e *editor) tableLines(cs charset.Charset) []string {
+	var buf bytes.Buffer
+	buf.WriteString("   0 1 2 3 4 5 6 7 8 9 a b c d e f\n")
+	for row := range fontpic.CharsetSz / 16 {
+		fmt.Fprintf(&buf, "%x_", row)
+		for col := range 16 {
+			ch := byte(row*16 + col)
+			s := printable(cs, ch)
+			if ch < e.doc.first || ch > e.doc.last {
+				s = " "
+			}
+			if ch == e.ch {
+				s = sgrReverse + s + sgrReset
+			}
+			buf.WriteString(" " + s)
+		}
+		buf.WriteString("\n")
+	}
+	return strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+}
+
+// printable returns the rune of the character in the charset, or '.' if it
+// is not printable.
+func printable(cs charset.Charset, ch byte) string {
+	r := cs.Rune(ch)
+	if !unicode.IsPrint(r) {
+		return "."
+	}
+	return string(r)
+}
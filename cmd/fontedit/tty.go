@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// tty.go switches the terminal to the character mode with stty, so that the
+// editor does not depend on any terminal libraries.
+
+// stty runs stty on the controlling terminal with the arguments and returns
+// its output.
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("stty %s: %w", strings.Join(args, " "), err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// rawMode disables the line buffering, the echo and the signal keys of the
+// terminal, so that Ctrl-C is handled by the editor.  It returns the
+// function, that restores the previous state.
+func rawMode() (restore func(), err error) {
+	state, err := stty("-g")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := stty("-icanon", "-echo", "-isig", "min", "1"); err != nil {
+		return nil, err
+	}
+	return func() { stty(state) }, nil
+}
+
+// termSize returns the number of columns and rows of the terminal, or 80x24,
+// if it is unknown.
+func termSize() (cols, rows int) {
+	out, err := stty("size")
+	if err != nil {
+		return 80, 24
+	}
+	if _, err := fmt.Sscanf(out, "%d %d", &rows, &cols); err != nil || cols == 0 || rows == 0 {
+		return 80, 24
+	}
+	return cols, rows
+}
@@ -0,0 +1,84 @@
+// Command fontgen generates the source code, that defines the font: Go
+// source, C header or NASM/ca65 include.
+//
+// Usage:
+//
+//	fontgen -f 08X16.FNT -charset 866 -format c -name font8x16 -o font8x16.h
+//	fontgen -font microfont -format go -name Microfont -pkg fonts
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/rusq/fontpic"
+)
+
+var (
+	fontfile  = flag.String("f", "", "FNT font file")
+	fontWidth = flag.Int("w", 8, "FNT font width")
+	charset   = flag.String("charset", "", "FNT font charset, i.e. 866")
+	fontName  = flag.String("font", "", "name of the registered font, i.e. 8x14 or microfont")
+	lang      = flag.String("format", "go", "output format: go, c, nasm or ca65")
+	name      = flag.String("name", "Font", "font identifier")
+	pkg       = flag.String("pkg", "fonts", "Go package name")
+	output    = flag.String("o", "", "output file, stdout if empty")
+)
+
+func main() {
+	flag.Parse()
+
+	format, err := fontpic.ParseCodeFormat(*lang)
+	if err != nil {
+		log.Fatal(err)
+	}
+	font, err := loadFont()
+	if err != nil {
+		log.Fatal(err)
+	}
+	opts := fontpic.CodeOptions{Format: format, Name: *name, Package: *pkg}
+	if *output == "" {
+		err = font.WriteCode(os.Stdout, opts)
+	} else {
+		err = writeFile(*output, font, opts)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// writeFile writes the code to the file filename.  The file is removed, if
+// the code can not be written, so that no partial output is left behind.
+func writeFile(filename string, font *fontpic.FNT, opts fontpic.CodeOptions) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := font.WriteCode(f, opts); err != nil {
+		f.Close()
+		os.Remove(filename)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(filename)
+		return err
+	}
+	return nil
+}
+
+func loadFont() (*fontpic.FNT, error) {
+	if *fontName != "" {
+		return fontpic.LookupFont(*fontName)
+	}
+	if *fontfile == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	f, err := fontpic.LoadFnt(*fontfile, *fontWidth)
+	if err != nil {
+		return nil, err
+	}
+	f.Charset = *charset
+	return f, nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/rusq/fontpic"
+)
+
+// convertExt maps the output file extensions to the convert formats.
+var convertExt = map[string]string{
+	".fnt": "fnt",
+	".ttf": "ttf",
+	".flf": "flf",
+	".go":  "go",
+	".h":   "c",
+	".asm": "nasm",
+	".inc": "nasm",
+	".s":   "ca65",
+}
+
+func runConvert(args []string) error {
+	var (
+		fs     = newFlagSet("convert", "")
+		ff     fontFlags
+		to     = fs.String("to", "", "output format: fnt, ttf, flf, go, c, nasm or ca65 (default from the output file extension)")
+		name   = fs.String("name", "", "font family name of ttf, or the identifier of the source code")
+		pkg    = fs.String("pkg", "fonts", "package name of the Go source")
+		output = fs.String("o", "", "output file, stdout if empty")
+	)
+	ff.register(fs)
+	fs.Parse(args)
+
+	format := strings.ToLower(*to)
+	if format == "" {
+		format = convertExt[strings.ToLower(filepath.Ext(*output))]
+	}
+	if format == "" {
+		return errors.New("output format is not specified")
+	}
+	f, err := ff.load()
+	if err != nil {
+		return err
+	}
+	return writeOutput(*output, func(w io.Writer) error {
+		switch format {
+		case "fnt":
+			_, err := f.WriteTo(w)
+			return err
+		case "ttf":
+			family := *name
+			if family == "" {
+				family = fontName(ff.name)
+			}
+			return f.WriteTTF(w, fontpic.TTFOptions{Family: family})
+		case "flf":
+			return f.WriteFLF(w, fontpic.BannerASCII)
+		}
+		cf, err := fontpic.ParseCodeFormat(format)
+		if err != nil {
+			return fmt.Errorf("unsupported output format: %s", format)
+		}
+		return f.WriteCode(w, fontpic.CodeOptions{Format: cf, Name: *name, Package: *pkg})
+	})
+}
+
+// fontName returns the font name without the directory and the extension.
+func fontName(name string) string {
+	return strings.TrimSuffix(filepath.Base(name), filepath.Ext(name))
+}
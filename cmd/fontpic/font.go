@@ -0,0 +1,64 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/image/font/opentype"
+
+	"github.com/rusq/fontpic"
+)
+
+// fontFlags are the flags, that select the font.
+type fontFlags struct {
+	name    string
+	width   int
+	height  int
+	charset string
+}
+
+func (ff *fontFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&ff.name, "f", "8x16", "registered font name, FNT file or TrueType/OpenType file")
+	fs.IntVar(&ff.width, "w", 8, "character width of the FNT or the rasterised font")
+	fs.IntVar(&ff.height, "height", 16, "character height of the rasterised font")
+	fs.StringVar(&ff.charset, "cp", "", "code page of the font, i.e. 866, overrides the font charset")
+}
+
+// load loads the font: the registered one, or the font file.
+func (ff *fontFlags) load() (*fontpic.FNT, error) {
+	f, err := fontpic.LookupFont(ff.name)
+	if errors.Is(err, fontpic.ErrFontNotFound) {
+		switch strings.ToLower(filepath.Ext(ff.name)) {
+		case ".ttf", ".otf":
+			f, err = ff.rasterize()
+		default:
+			f, err = fontpic.LoadFnt(ff.name, ff.width)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if ff.charset != "" {
+		// the registered fonts are shared, so the charset is set on the copy.
+		cp := *f
+		cp.Charset = ff.charset
+		f = &cp
+	}
+	return f, nil
+}
+
+func (ff *fontFlags) rasterize() (*fontpic.FNT, error) {
+	data, err := os.ReadFile(ff.name)
+	if err != nil {
+		return nil, err
+	}
+	sf, err := opentype.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ff.name, err)
+	}
+	return fontpic.Rasterize(sf, fontpic.RasterOptions{Width: ff.width, Height: ff.height, Charset: ff.charset})
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/rusq/fontpic"
+)
+
+func runInfo(args []string) error {
+	var (
+		fs = newFlagSet("info", "")
+		ff fontFlags
+	)
+	ff.register(fs)
+	fs.Parse(args)
+
+	f, err := ff.load()
+	if err != nil {
+		return err
+	}
+	var glyphs []byte
+	for ch, data := range f.Chars {
+		if slices.ContainsFunc(data, func(b byte) bool { return b != 0 }) {
+			glyphs = append(glyphs, byte(ch))
+		}
+	}
+	charset := f.Charset
+	if charset == "" {
+		charset = "none"
+	}
+	spacing := "monospaced"
+	if len(f.Metrics) == fontpic.CharsetSz {
+		spacing = "proportional"
+	}
+	w := os.Stdout
+	fmt.Fprintf(w, "font:     %s\n", ff.name)
+	fmt.Fprintf(w, "size:     %dx%d, %d bytes\n", f.Width, f.Height, len(f.Bytes()))
+	fmt.Fprintf(w, "charset:  %s\n", charset)
+	fmt.Fprintf(w, "spacing:  %s\n", spacing)
+	fmt.Fprintf(w, "kerning:  %d pairs\n", len(f.Kerning))
+	fmt.Fprintf(w, "glyphs:   %d of %d\n", len(glyphs), fontpic.CharsetSz)
+	fmt.Fprintf(w, "coverage: %s\n", codeRanges(glyphs))
+	return nil
+}
+
+func runFonts(args []string) error {
+	fs := newFlagSet("fonts", "")
+	fs.Parse(args)
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tFAMILY\tSTYLE\tSIZE\tCHARSET\tLICENSE")
+	for _, fi := range fontpic.Fonts() {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%dx%d\t%s\t%s\n", fi.Name, fi.Family, fi.Style, fi.Width, fi.Height, fi.Charset, fi.License)
+	}
+	return tw.Flush()
+}
+
+// codeRanges returns the sorted character codes as the list of ranges, i.e.
+// "0x01-0x1f, 0x21-0xfe".
+func codeRanges(codes []byte) string {
+	var ranges []string
+	for i := 0; i < len(codes); {
+		j := i
+		for j+1 < len(codes) && codes[j+1] == codes[j]+1 {
+			j++
+		}
+		if i == j {
+			ranges = append(ranges, fmt.Sprintf("0x%02x", codes[i]))
+		} else {
+			ranges = append(ranges, fmt.Sprintf("0x%02x-0x%02x", codes[i], codes[j]))
+		}
+		i = j + 1
+	}
+	if len(ranges) == 0 {
+		return "none"
+	}
+	return strings.Join(ranges, ", ")
+}
@@ -0,0 +1,72 @@
+// Command fontpic renders the text with the bitmap fonts, draws the glyph
+// sheets, converts the fonts between the formats and shows the font
+// information.
+//
+// Usage:
+//
+//	fontpic render [-f font] [-fg colour] [-bg colour] [-scale n] [-markup] [-o out.png] text...
+//	fontpic sample [-f font] [-n perLine] [-format svg] [-o sample.svg]
+//	fontpic convert -f font.ttf -w 8 -height 16 -to fnt -o font.fnt
+//	fontpic info -f 08X14.FNT
+//	fontpic fonts
+//
+// The font is the name of the registered font, i.e. 8x16 or microfont, the raw
+// FNT file, or the TrueType/OpenType font, that is rasterised to the cell of
+// -w by -height pixels.  Run "fontpic <command> -h" for the command flags.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+)
+
+// command is the subcommand of the tool.
+type command struct {
+	name  string
+	short string
+	run   func(args []string) error
+}
+
+var commands = []command{
+	{"render", "render the text to the image", runRender},
+	{"sample", "render the sheet of all glyphs of the font", runSample},
+	{"convert", "convert the font to another format", runConvert},
+	{"info", "show the font dimensions, glyph coverage and charset", runInfo},
+	{"fonts", "list the registered fonts", runFonts},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	for _, cmd := range commands {
+		if cmd.name == os.Args[1] {
+			if err := cmd.run(os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return
+		}
+	}
+	usage()
+	os.Exit(2)
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: %s <command> [flags]\n\nCommands:\n", os.Args[0])
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-8s %s\n", cmd.name, cmd.short)
+	}
+}
+
+// newFlagSet returns the flag set of the command.
+func newFlagSet(name, args string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s %s [flags] %s\n\nFlags:\n", os.Args[0], name, args)
+		fs.PrintDefaults()
+	}
+	return fs
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rusq/fontpic"
+)
+
+// outputFormat returns the output format: the format flag, or the one
+// matching the extension of the output file, or png.
+func outputFormat(format, filename string) string {
+	if format != "" {
+		return strings.ToLower(format)
+	}
+	switch ext := strings.ToLower(filepath.Ext(filename)); ext {
+	case ".gif", ".svg":
+		return ext[1:]
+	case ".txt":
+		return "banner"
+	default:
+		return "png"
+	}
+}
+
+// nopCloser is the stdout, that is not closed.
+type nopCloser struct{ io.Writer }
+
+func (nopCloser) Close() error { return nil }
+
+// create creates the output file, or returns stdout, if the filename is
+// empty.
+func create(filename string) (io.WriteCloser, error) {
+	if filename == "" {
+		return nopCloser{os.Stdout}, nil
+	}
+	return os.Create(filename)
+}
+
+// writeOutput creates the output file, calls fn with it and closes it.
+func writeOutput(filename string, fn func(w io.Writer) error) error {
+	w, err := create(filename)
+	if err != nil {
+		return err
+	}
+	if err := fn(w); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// writeImage writes the image in the format: png, gif, or the terminal mode.
+func writeImage(w io.Writer, img image.Image, format string) error {
+	switch format {
+	case "png":
+		return png.Encode(w, img)
+	case "gif":
+		return fontpic.EncodeGIF(w, img)
+	}
+	mode, err := fontpic.ParseTermMode(format)
+	if err != nil {
+		return fmt.Errorf("unsupported output format: %s", format)
+	}
+	return fontpic.WriteTerm(w, img, mode)
+}
+
+// parseColors parses the foreground and background colours.
+func parseColors(fg, bg string) (fgc, bgc color.Color, err error) {
+	if fgc, err = fontpic.ParseColor(fg); err != nil {
+		return nil, nil, err
+	}
+	if bgc, err = fontpic.ParseColor(bg); err != nil {
+		return nil, nil, err
+	}
+	return fgc, bgc, nil
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/rusq/fontpic"
+	"github.com/rusq/fontpic/charset"
+)
+
+func runRender(args []string) error {
+	var (
+		fs       = newFlagSet("render", "[text...]")
+		ff       fontFlags
+		input    = fs.String("i", "", "input text file, if the text is not given in the arguments, stdin is read")
+		fg       = fs.String("fg", "lightgrey", "foreground colour, i.e. #ffaa00 or yellow")
+		bg       = fs.String("bg", "black", "background colour, or transparent")
+		sx       = fs.Int("sx", 0, "horizontal spacing between the characters")
+		sy       = fs.Int("sy", 0, "vertical spacing between the lines")
+		scale    = fs.Int("scale", 1, "integer scale of the output")
+		scaler   = fs.String("scaler", "", "pixel-art upscaler of the font: nearest, scale2x, scale3x, eagle or sfx")
+		vertical = fs.Bool("vertical", false, "stack the characters top to bottom")
+		markup   = fs.Bool("markup", false, "the text has the markup tags, i.e. [b]bold[/b] or [color=red]red[/color]")
+		format   = fs.String("format", "", "output format: png, gif, svg, banner, halfblock, braille or sixel (default from the output file extension, or png)")
+		output   = fs.String("o", "", "output file, stdout if empty")
+	)
+	ff.register(fs)
+	fs.Parse(args)
+
+	text, err := readText(fs.Args(), *input)
+	if err != nil {
+		return err
+	}
+	f, err := ff.load()
+	if err != nil {
+		return err
+	}
+	if *scaler != "" {
+		s, err := fontpic.ParseScaler(*scaler)
+		if err != nil {
+			return err
+		}
+		f = f.Upscale(s)
+	}
+	fgc, bgc, err := parseColors(*fg, *bg)
+	if err != nil {
+		return err
+	}
+	cs, _ := charset.ByName(f.Charset)
+	data := cs.Translate(text)
+
+	c := fontpic.NewCanvas(f).
+		WithForeground(fgc).
+		WithBackground(bgc).
+		WithSpacing(*sx, *sy).
+		WithVertical(*vertical)
+	of := outputFormat(*format, *output)
+	if *markup {
+		spans, err := fontpic.ParseMarkup(data)
+		if err != nil {
+			return err
+		}
+		return writeOutput(*output, func(w io.Writer) error {
+			switch of {
+			case "svg":
+				c.Scale.X, c.Scale.Y = *scale, *scale
+				return c.WriteSpansSVG(w, spans, fontpic.SVGOptions{Symbols: true})
+			case "banner":
+				return errors.New("banner output does not support markup")
+			}
+			return writeImage(w, fontpic.Enlarge(c.RenderSpans(spans).Image(), *scale), of)
+		})
+	}
+	return writeOutput(*output, func(w io.Writer) error {
+		switch of {
+		case "svg":
+			c.Scale.X, c.Scale.Y = *scale, *scale
+			return c.WriteSVG(w, data, fontpic.SVGOptions{Symbols: true})
+		case "banner":
+			_, err := io.WriteString(w, f.Banner(data, fontpic.BannerBlock))
+			return err
+		}
+		return writeImage(w, fontpic.Enlarge(c.RenderText(data).Image(), *scale), of)
+	})
+}
+
+// readText returns the text from the arguments, the file, or stdin.  The
+// trailing newline of the file is removed.
+func readText(args []string, filename string) (string, error) {
+	if len(args) > 0 {
+		return strings.Join(args, " "), nil
+	}
+	var (
+		data []byte
+		err  error
+	)
+	if filename != "" {
+		data, err = os.ReadFile(filename)
+	} else {
+		data, err = io.ReadAll(os.Stdin)
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(bytes.TrimRight(data, "\r\n")), nil
+}
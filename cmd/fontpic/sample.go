@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/rusq/fontpic"
+)
+
+func runSample(args []string) error {
+	var (
+		fs      = newFlagSet("sample", "")
+		ff      fontFlags
+		perLine = fs.Int("n", 16, "characters per line")
+		fg      = fs.String("fg", "lightgrey", "foreground colour, i.e. #ffaa00 or yellow")
+		bg      = fs.String("bg", "black", "background colour, or transparent")
+		scale   = fs.Int("scale", 1, "integer scale of the output")
+		format  = fs.String("format", "", "output format: png, gif, svg, halfblock, braille or sixel (default from the output file extension, or png)")
+		output  = fs.String("o", "", "output file, stdout if empty")
+	)
+	ff.register(fs)
+	fs.Parse(args)
+	if *perLine < 1 || *perLine > fontpic.CharsetSz {
+		return fmt.Errorf("-n must be from 1 to %d", fontpic.CharsetSz)
+	}
+
+	f, err := ff.load()
+	if err != nil {
+		return err
+	}
+	fgc, bgc, err := parseColors(*fg, *bg)
+	if err != nil {
+		return err
+	}
+	of := outputFormat(*format, *output)
+	return writeOutput(*output, func(w io.Writer) error {
+		if of == "svg" {
+			return f.WriteSampleColorSVG(w, *perLine, fgc, bgc, fontpic.SVGOptions{Symbols: true, Scale: *scale})
+		}
+		return writeImage(w, fontpic.Enlarge(f.SampleColor(*perLine, fgc, bgc), *scale), of)
+	})
+}
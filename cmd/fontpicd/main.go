@@ -0,0 +1,39 @@
+// Command fontpicd is the HTTP server, that renders the text with the bitmap
+// fonts, so that the retro styled images can be embedded in the dashboards
+// and the chat bots.
+//
+// Endpoints:
+//
+//	GET /render?text=Hello&font=8x16&fg=yellow&bg=blue&scale=2&format=png
+//	GET /fonts
+//	GET /sample/{font}?n=16&fg=white&bg=black&scale=1&format=png
+//
+// The format is png, gif or svg, the colours are in the hex notation or the
+// names of the CGA colours.  The responses are cached by the clients with
+// the ETag and Cache-Control headers.
+//
+// Usage:
+//
+//	fontpicd [-addr :8080]
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"time"
+)
+
+var addr = flag.String("addr", ":8080", "listen address")
+
+func main() {
+	flag.Parse()
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           newServer(),
+		ReadHeaderTimeout: 5 * time.Second,
+		WriteTimeout:      30 * time.Second,
+	}
+	log.Printf("listening on %s", *addr)
+	log.Fatal(srv.ListenAndServe())
+}
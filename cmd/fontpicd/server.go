@@ -0,0 +1,252 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rusq/fontpic"
+	"github.com/rusq/fontpic/charset"
+)
+
+// Limits of the requests.
+const (
+	maxTextLen = 1024    // runes
+	maxScale   = 16      // times
+	maxPixels  = 4 << 20 // pixels of the scaled image
+	maxPerLine = 256     // characters per line of the sample
+)
+
+// cacheControl is the Cache-Control of the rendered images.  The registered
+// fonts never change, so neither does the image for the same request.
+const cacheControl = "public, max-age=86400"
+
+// contentTypes are the content types of the output formats.
+var contentTypes = map[string]string{
+	"png": "image/png",
+	"gif": "image/gif",
+	"svg": "image/svg+xml",
+}
+
+// newServer returns the handler of the server.
+func newServer() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /render", handleRender)
+	mux.HandleFunc("GET /fonts", handleFonts)
+	mux.HandleFunc("GET /sample/{font}", handleSample)
+	return mux
+}
+
+// imageParams are the parameters common for the images.
+type imageParams struct {
+	font   string
+	fnt    *fontpic.FNT
+	fg, bg color.Color
+	scale  int
+	format string
+}
+
+// parseImageParams parses the query parameters of the image.
+func parseImageParams(r *http.Request, fontName string) (imageParams, error) {
+	q := r.URL.Query()
+	p := imageParams{font: fontName, scale: 1, format: "png"}
+	var err error
+	if p.fnt, err = fontpic.LookupFont(p.font); err != nil {
+		return p, err
+	}
+	if p.fg, err = fontpic.ParseColor(valueOr(q, "fg", "lightgrey")); err != nil {
+		return p, err
+	}
+	if p.bg, err = fontpic.ParseColor(valueOr(q, "bg", "black")); err != nil {
+		return p, err
+	}
+	if s := q.Get("scale"); s != "" {
+		if p.scale, err = strconv.Atoi(s); err != nil || p.scale < 1 || p.scale > maxScale {
+			return p, fmt.Errorf("scale must be from 1 to %d", maxScale)
+		}
+	}
+	p.format = strings.ToLower(valueOr(q, "format", "png"))
+	if _, ok := contentTypes[p.format]; !ok {
+		return p, fmt.Errorf("unsupported format: %s", p.format)
+	}
+	return p, nil
+}
+
+// etag returns the entity tag of the image with the parameters and the
+// values, that identify the content.
+func (p imageParams) etag(values ...any) string {
+	h := sha256.New()
+	fg, bg := color.RGBAModel.Convert(p.fg), color.RGBAModel.Convert(p.bg)
+	fmt.Fprintf(h, "%s\x00%v\x00%v\x00%d\x00%s", strings.ToLower(p.font), fg, bg, p.scale, p.format)
+	for _, v := range values {
+		fmt.Fprintf(h, "\x00%v", v)
+	}
+	return `"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+}
+
+// checkSize returns the error, if the image of the size is too large.
+func (p imageParams) checkSize(w, h int) error {
+	if w*h*p.scale*p.scale > maxPixels {
+		return fmt.Errorf("image is too large: %dx%d", w*p.scale, h*p.scale)
+	}
+	return nil
+}
+
+// write writes the image, or calls fn for svg, to w, unless the client has
+// the image with the same ETag.
+func (p imageParams) write(w http.ResponseWriter, r *http.Request, etag string, img func() image.Image, svg func(io.Writer) error) {
+	h := w.Header()
+	h.Set("ETag", etag)
+	h.Set("Cache-Control", cacheControl)
+	if matchETag(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	var (
+		buf bytes.Buffer
+		err error
+	)
+	switch p.format {
+	case "svg":
+		err = svg(&buf)
+	case "gif":
+		err = fontpic.EncodeGIF(&buf, fontpic.Enlarge(img(), p.scale))
+	default:
+		err = png.Encode(&buf, fontpic.Enlarge(img(), p.scale))
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.Set("Content-Type", contentTypes[p.format])
+	h.Set("Content-Length", strconv.Itoa(buf.Len()))
+	if r.Method != http.MethodHead {
+		w.Write(buf.Bytes())
+	}
+}
+
+func handleRender(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	p, err := parseImageParams(r, valueOr(q, "font", "8x16"))
+	if err != nil {
+		httpError(w, err)
+		return
+	}
+	text := q.Get("text")
+	if text == "" {
+		http.Error(w, "text is required", http.StatusBadRequest)
+		return
+	}
+	if n := utf8.RuneCountInString(text); n > maxTextLen {
+		http.Error(w, fmt.Sprintf("text is too long: %d characters, maximum is %d", n, maxTextLen), http.StatusBadRequest)
+		return
+	}
+	cs, _ := charset.ByName(p.fnt.Charset)
+	data := bytes.ReplaceAll(cs.Translate(text), []byte("\t"), []byte("        "))
+	lines := bytes.Split(bytes.ReplaceAll(data, []byte("\r"), nil), []byte("\n"))
+
+	c := fontpic.NewCanvas(p.fnt).
+		WithForeground(p.fg).
+		WithBackground(p.bg).
+		CalcSize(lines)
+	if err := p.checkSize(c.Width, c.Height); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	p.write(w, r, p.etag(text),
+		func() image.Image {
+			return c.Render(lines).Image()
+		},
+		func(w io.Writer) error {
+			c.Scale = image.Pt(p.scale, p.scale)
+			return c.WriteSVG(w, bytes.Join(lines, []byte("\n")), fontpic.SVGOptions{Symbols: true})
+		})
+}
+
+func handleSample(w http.ResponseWriter, r *http.Request) {
+	p, err := parseImageParams(r, r.PathValue("font"))
+	if err != nil {
+		httpError(w, err)
+		return
+	}
+	perLine := 16
+	if s := r.URL.Query().Get("n"); s != "" {
+		if perLine, err = strconv.Atoi(s); err != nil || perLine < 1 || perLine > maxPerLine {
+			http.Error(w, fmt.Sprintf("n must be from 1 to %d", maxPerLine), http.StatusBadRequest)
+			return
+		}
+	}
+	rows := (fontpic.CharsetSz + perLine - 1) / perLine
+	if err := p.checkSize(perLine*(p.fnt.Width+1), rows*(p.fnt.Height+1)); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	p.write(w, r, p.etag("sample", perLine),
+		func() image.Image {
+			return p.fnt.SampleColor(perLine, p.fg, p.bg)
+		},
+		func(w io.Writer) error {
+			return p.fnt.WriteSampleColorSVG(w, perLine, p.fg, p.bg, fontpic.SVGOptions{Symbols: true, Scale: p.scale})
+		})
+}
+
+// fontInfo is the font in the /fonts listing.
+type fontInfo struct {
+	Name    string `json:"name"`
+	Family  string `json:"family"`
+	Style   string `json:"style"`
+	Width   int    `json:"width"`
+	Height  int    `json:"height"`
+	Charset string `json:"charset,omitempty"`
+	License string `json:"license,omitempty"`
+	Source  string `json:"source,omitempty"`
+}
+
+func handleFonts(w http.ResponseWriter, r *http.Request) {
+	var list []fontInfo
+	for _, fi := range fontpic.Fonts() {
+		list = append(list, fontInfo(fi))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	json.NewEncoder(w).Encode(list)
+}
+
+// valueOr returns the query value of the key, or def, if it is empty.
+func valueOr(q map[string][]string, key, def string) string {
+	if v := q[key]; len(v) > 0 && v[0] != "" {
+		return v[0]
+	}
+	return def
+}
+
+// matchETag reports whether the If-None-Match header matches the etag.
+func matchETag(header, etag string) bool {
+	for _, t := range strings.Split(header, ",") {
+		if t = strings.TrimSpace(t); t == etag || t == "*" || t == "W/"+etag {
+			return true
+		}
+	}
+	return false
+}
+
+// httpError writes the error of the parameters, with the status 404 for the
+// unknown fonts.
+func httpError(w http.ResponseWriter, err error) {
+	status := http.StatusBadRequest
+	if errors.Is(err, fontpic.ErrFontNotFound) {
+		status = http.StatusNotFound
+	}
+	http.Error(w, err.Error(), status)
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"encoding/json"
+	"image/gif"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/rusq/fontpic"
+)
+
+func get(t *testing.T, h http.Handler, target string, header http.Header) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestServer_render(t *testing.T) {
+	h := newServer()
+	tests := []struct {
+		name        string
+		query       url.Values
+		wantStatus  int
+		contentType string
+	}{
+		{"png", url.Values{"text": {"Hi"}}, http.StatusOK, "image/png"},
+		{"gif", url.Values{"text": {"Hi"}, "format": {"gif"}, "fg": {"#ff0"}}, http.StatusOK, "image/gif"},
+		{"svg", url.Values{"text": {"Hi"}, "format": {"svg"}, "scale": {"3"}}, http.StatusOK, "image/svg+xml"},
+		{"cyrillic", url.Values{"text": {"Привет"}, "font": {"8x8"}}, http.StatusOK, "image/png"},
+		{"image font", url.Values{"text": {"Hi"}, "font": {"microfont"}}, http.StatusOK, "image/png"},
+		{"no text", url.Values{}, http.StatusBadRequest, ""},
+		{"unknown font", url.Values{"text": {"Hi"}, "font": {"comic"}}, http.StatusNotFound, ""},
+		{"bad colour", url.Values{"text": {"Hi"}, "fg": {"mauve"}}, http.StatusBadRequest, ""},
+		{"bad format", url.Values{"text": {"Hi"}, "format": {"bmp"}}, http.StatusBadRequest, ""},
+		{"scale too large", url.Values{"text": {"Hi"}, "scale": {"17"}}, http.StatusBadRequest, ""},
+		{"text too long", url.Values{"text": {strings.Repeat("x", maxTextLen+1)}}, http.StatusBadRequest, ""},
+		{"image too large", url.Values{"text": {strings.Repeat("x", maxTextLen)}, "scale": {"16"}}, http.StatusBadRequest, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := get(t, h, "/render?"+tt.query.Encode(), nil)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != tt.contentType {
+				t.Errorf("Content-Type = %q, want %q", ct, tt.contentType)
+			}
+			if rec.Header().Get("ETag") == "" || rec.Header().Get("Cache-Control") == "" {
+				t.Error("caching headers are missing")
+			}
+		})
+	}
+}
+
+func TestServer_renderImage(t *testing.T) {
+	rec := get(t, newServer(), "/render?text=Hi%0Athere&scale=2", nil)
+	img, err := png.Decode(rec.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// two lines of 5 characters of the 8x16 font, scaled twice.
+	if got := img.Bounds().Size(); got.X != 5*8*2 || got.Y != 2*16*2 {
+		t.Errorf("size = %v, want 80x64", got)
+	}
+
+	rec = get(t, newServer(), "/render?text=Hi&format=gif&fg=white&bg=blue", nil)
+	g, err := gif.Decode(rec.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r, _, b, _ := g.At(0, 0).RGBA(); r != 0 || b != 0xaaaa {
+		t.Errorf("background = %v, want blue", g.At(0, 0))
+	}
+}
+
+func TestServer_etag(t *testing.T) {
+	h := newServer()
+	first := get(t, h, "/render?text=Hi&fg=yellow", nil)
+	etag := first.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("no ETag")
+	}
+	tests := []struct {
+		name       string
+		target     string
+		match      string
+		wantStatus int
+	}{
+		{"same", "/render?text=Hi&fg=yellow", etag, http.StatusNotModified},
+		{"same colour in hex", "/render?text=Hi&fg=%23ffff55", etag, http.StatusNotModified},
+		{"one of the list", "/render?text=Hi&fg=yellow", `"abc", ` + etag, http.StatusNotModified},
+		{"other text", "/render?text=Ho&fg=yellow", etag, http.StatusOK},
+		{"other scale", "/render?text=Hi&fg=yellow&scale=2", etag, http.StatusOK},
+		{"no header", "/render?text=Hi&fg=yellow", "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := get(t, h, tt.target, http.Header{"If-None-Match": {tt.match}})
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if rec.Code == http.StatusNotModified && rec.Body.Len() != 0 {
+				t.Error("304 response has a body")
+			}
+		})
+	}
+}
+
+func TestServer_fonts(t *testing.T) {
+	rec := get(t, newServer(), "/fonts", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d", rec.Code)
+	}
+	var list []fontInfo
+	if err := json.NewDecoder(rec.Body).Decode(&list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != len(fontpic.Fonts()) {
+		t.Errorf("got %d fonts, want %d", len(list), len(fontpic.Fonts()))
+	}
+	for _, f := range list {
+		if f.Name == "8x14" && (f.Width != 8 || f.Height != 14 || f.Charset != "866" || f.Family != "KeyRus 8x14") {
+			t.Errorf("8x14 = %+v", f)
+		}
+	}
+}
+
+func TestServer_sample(t *testing.T) {
+	h := newServer()
+	tests := []struct {
+		target     string
+		wantStatus int
+	}{
+		{"/sample/8x8", http.StatusOK},
+		{"/sample/microfont?format=svg", http.StatusOK},
+		{"/sample/8x16?n=32&scale=2", http.StatusOK},
+		{"/sample/8x16?n=0", http.StatusBadRequest},
+		{"/sample/8x16?n=256&scale=16", http.StatusBadRequest},
+		{"/sample/comic", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			if rec := get(t, h, tt.target, nil); rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+		})
+	}
+	rec := get(t, h, "/sample/8x8?n=16", nil)
+	img, err := png.Decode(rec.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := img.Bounds().Size(); got.X != 16*9 || got.Y != 16*9 {
+		t.Errorf("size = %v, want 144x144", got)
+	}
+
+	// the scale applies to the SVG, the same as to the images.
+	one := get(t, h, "/sample/8x8?format=svg", nil).Body.String()
+	two := get(t, h, "/sample/8x8?format=svg&scale=2", nil).Body.String()
+	if !strings.Contains(one, `width="144"`) || !strings.Contains(two, `width="288"`) {
+		t.Errorf("SVG is not scaled:\n%.120s\n%.120s", one, two)
+	}
+}
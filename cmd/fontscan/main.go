@@ -0,0 +1,73 @@
+// Command fontscan searches the binary files, such as the video BIOS dumps
+// and the old programs, for the 8xN font tables.  It prints the offsets,
+// heights and scores of the candidates, and optionally writes the preview
+// sheets and the extracted fonts.
+//
+// Usage:
+//
+//	fontscan [-heights 8,14,16] [-min 0.5] [-preview] [-extract] bios.rom
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image/png"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/rusq/fontpic"
+)
+
+var (
+	heights  = flag.String("heights", "8,14,16", "comma separated character heights")
+	minScore = flag.Float64("min", 0.5, "minimal score of the candidate")
+	preview  = flag.Bool("preview", false, "write the preview sheet of each candidate")
+	extract  = flag.Bool("extract", false, "write each candidate as the FNT file")
+)
+
+func main() {
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	opts := fontpic.ScanOptions{MinScore: *minScore}
+	for _, s := range strings.Split(*heights, ",") {
+		h, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil {
+			log.Fatalf("invalid height: %s", s)
+		}
+		opts.Heights = append(opts.Heights, h)
+	}
+	data, err := os.ReadFile(flag.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	base := strings.TrimSuffix(filepath.Base(flag.Arg(0)), filepath.Ext(flag.Arg(0)))
+	for _, c := range fontpic.ScanFonts(data, opts) {
+		fmt.Printf("0x%08x\t8x%d\t%.2f\n", c.Offset, c.Height, c.Score)
+		name := fmt.Sprintf("%s_%08x_8x%02d", base, c.Offset, c.Height)
+		if *preview {
+			if err := writePreview(name+".png", c.Font); err != nil {
+				log.Fatal(err)
+			}
+		}
+		if *extract {
+			if err := os.WriteFile(name+".fnt", c.Font.Bytes(), 0o644); err != nil {
+				log.Fatal(err)
+			}
+		}
+	}
+}
+
+func writePreview(filename string, fnt *fontpic.FNT) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return png.Encode(f, fnt.Sample(16))
+}
@@ -0,0 +1,251 @@
+package fontpic
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"io"
+	"slices"
+	"strings"
+)
+
+// codegen.go contains the generators of the source code, that defines the
+// fonts in Go, C and assembler.
+
+// CodeFormat is the language of the generated source code.
+type CodeFormat int
+
+const (
+	// CodeGo is the Go source, that defines the FNT and its font.Face.
+	CodeGo CodeFormat = iota
+	// CodeC is the C header with the static arrays.
+	CodeC
+	// CodeNASM is the NASM include with the db directives.
+	CodeNASM
+	// CodeCA65 is the ca65 include with the .byte directives.
+	CodeCA65
+)
+
+func (c CodeFormat) String() string {
+	switch c {
+	case CodeGo:
+		return "go"
+	case CodeC:
+		return "c"
+	case CodeNASM:
+		return "nasm"
+	case CodeCA65:
+		return "ca65"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseCodeFormat returns the code format by its name, as returned by
+// [CodeFormat.String].
+func ParseCodeFormat(s string) (CodeFormat, error) {
+	for c := CodeGo; c <= CodeCA65; c++ {
+		if strings.EqualFold(s, c.String()) {
+			return c, nil
+		}
+	}
+	return 0, errors.New("unknown code format: " + s)
+}
+
+// CodeOptions are the options of the source code generator.
+type CodeOptions struct {
+	Format CodeFormat
+	// Name is the identifier of the font.  The names of the other symbols
+	// are derived from it, i.e. Name_chars in C.  If empty, "Font" is used.
+	Name string
+	// Package is the package name of the Go source.  If empty, "fonts" is
+	// used.
+	Package string
+}
+
+const codeHeader = "Code generated by fontpic; DO NOT EDIT."
+
+// WriteCode writes the source code, that defines the font, to w.  The
+// characters are stored in the FNT layout: Height rows per character, each
+// row is the big-endian integer of the whole bytes, with the rightmost pixel
+// in the least significant bit.  The metrics and the kerning are included,
+// if the font has them.
+func (f *FNT) WriteCode(w io.Writer, opts CodeOptions) error {
+	if opts.Name == "" {
+		opts.Name = "Font"
+	}
+	if opts.Package == "" {
+		opts.Package = "fonts"
+	}
+	var buf bytes.Buffer
+	switch opts.Format {
+	case CodeGo:
+		f.writeGo(&buf, opts)
+		src, err := format.Source(buf.Bytes())
+		if err != nil {
+			return err
+		}
+		buf.Reset()
+		buf.Write(src)
+	case CodeC:
+		f.writeC(&buf, opts)
+	case CodeNASM, CodeCA65:
+		f.writeAsm(&buf, opts)
+	default:
+		return errors.New("unknown code format")
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// WriteCode writes the source code, that defines the font, to w.  See
+// [FNT.WriteCode].
+func (f *ImageFont) WriteCode(w io.Writer, opts CodeOptions) error {
+	return f.ToFnt().WriteCode(w, opts)
+}
+
+// kernPairs returns the kerning pairs in the stable order.
+func (f *FNT) kernPairs() []KernPair {
+	pairs := make([]KernPair, 0, len(f.Kerning))
+	for p := range f.Kerning {
+		pairs = append(pairs, p)
+	}
+	slices.SortFunc(pairs, func(a, b KernPair) int {
+		return int(a[0])<<8 + int(a[1]) - (int(b[0])<<8 + int(b[1]))
+	})
+	return pairs
+}
+
+// hexBytes returns the bytes as the comma separated list of hex numbers with
+// the prefix, i.e. "0x" or "$".
+func hexBytes(data []byte, prefix string) string {
+	var sb strings.Builder
+	for i, v := range data {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		fmt.Fprintf(&sb, "%s%02x", prefix, v)
+	}
+	return sb.String()
+}
+
+func (f *FNT) writeGo(buf *bytes.Buffer, opts CodeOptions) {
+	fmt.Fprintf(buf, "// %s\n\npackage %s\n\n", codeHeader, opts.Package)
+	buf.WriteString("import (\n\t\"golang.org/x/image/font\"\n\n\t\"github.com/rusq/fontpic\"\n)\n\n")
+	fmt.Fprintf(buf, "// %s is the %dx%d font.\n", opts.Name, f.Width, f.Height)
+	fmt.Fprintf(buf, "var %s = &fontpic.FNT{\n", opts.Name)
+	fmt.Fprintf(buf, "Width: %d,\nHeight: %d,\nCharset: %q,\n", f.Width, f.Height, f.Charset)
+	buf.WriteString("Chars: [fontpic.CharsetSz][]byte{\n")
+	for ch, data := range f.Chars {
+		fmt.Fprintf(buf, "{%s}, // 0x%02x\n", hexBytes(data, "0x"), ch)
+	}
+	buf.WriteString("},\n")
+	if len(f.Metrics) > 0 {
+		buf.WriteString("Metrics: []fontpic.GlyphMetrics{\n")
+		for ch, m := range f.Metrics {
+			fmt.Fprintf(buf, "{Left: %d, Advance: %d}, // 0x%02x\n", m.Left, m.Advance, ch)
+		}
+		buf.WriteString("},\n")
+	}
+	if len(f.Kerning) > 0 {
+		buf.WriteString("Kerning: fontpic.Kerning{\n")
+		for _, p := range f.kernPairs() {
+			fmt.Fprintf(buf, "{0x%02x, 0x%02x}: %d,\n", p[0], p[1], f.Kerning[p])
+		}
+		buf.WriteString("},\n")
+	}
+	buf.WriteString("}\n\n")
+	fmt.Fprintf(buf, "// %sFace is the font.Face of %s.\n", opts.Name, opts.Name)
+	fmt.Fprintf(buf, "var %sFace font.Face = %s.Face()\n", opts.Name, opts.Name)
+}
+
+func (f *FNT) writeC(buf *bytes.Buffer, opts CodeOptions) {
+	var (
+		macro = strings.ToUpper(opts.Name)
+		size  = f.Height * f.stride()
+	)
+	fmt.Fprintf(buf, "/* %s */\n\n#ifndef %s_H\n#define %s_H\n\n#include <stdint.h>\n\n", codeHeader, macro, macro)
+	fmt.Fprintf(buf, "#define %s_WIDTH %d\n", macro, f.Width)
+	fmt.Fprintf(buf, "#define %s_HEIGHT %d\n", macro, f.Height)
+	fmt.Fprintf(buf, "#define %s_STRIDE %d /* bytes per row */\n\n", macro, f.stride())
+	fmt.Fprintf(buf, "static const uint8_t %s_chars[%d][%d] = {\n", opts.Name, CharsetSz, size)
+	for ch, data := range f.Chars {
+		fmt.Fprintf(buf, "\t{%s}, /* 0x%02x */\n", hexBytes(data, "0x"), ch)
+	}
+	buf.WriteString("};\n")
+	if len(f.Metrics) > 0 {
+		var left, adv = make([]byte, len(f.Metrics)), make([]byte, len(f.Metrics))
+		for i, m := range f.Metrics {
+			left[i], adv[i] = byte(m.Left), byte(m.Advance)
+		}
+		fmt.Fprintf(buf, "\n#define %s_PROPORTIONAL 1\n\n", macro)
+		fmt.Fprintf(buf, "static const uint8_t %s_left[%d] = {\n", opts.Name, len(left))
+		writeCRows(buf, left)
+		fmt.Fprintf(buf, "static const uint8_t %s_advance[%d] = {\n", opts.Name, len(adv))
+		writeCRows(buf, adv)
+	}
+	if len(f.Kerning) > 0 {
+		fmt.Fprintf(buf, "\n#define %s_KERNING_COUNT %d\n\n", macro, len(f.Kerning))
+		fmt.Fprintf(buf, "static const struct {\n\tuint8_t first, second;\n\tint8_t adjust;\n} %s_kerning[%d] = {\n", opts.Name, len(f.Kerning))
+		for _, p := range f.kernPairs() {
+			fmt.Fprintf(buf, "\t{0x%02x, 0x%02x, %d},\n", p[0], p[1], f.Kerning[p])
+		}
+		buf.WriteString("};\n")
+	}
+	fmt.Fprintf(buf, "\n#endif /* %s_H */\n", macro)
+}
+
+// writeCRows writes the array elements, 16 per line, and the closing brace.
+func writeCRows(buf *bytes.Buffer, data []byte) {
+	for chunk := range slices.Chunk(data, 16) {
+		fmt.Fprintf(buf, "\t%s,\n", hexBytes(chunk, "0x"))
+	}
+	buf.WriteString("};\n")
+}
+
+func (f *FNT) writeAsm(buf *bytes.Buffer, opts CodeOptions) {
+	var (
+		macro              = strings.ToUpper(opts.Name)
+		prefix, db, define = "0x", "db", "%s_%s equ %d\n"
+	)
+	if opts.Format == CodeCA65 {
+		prefix, db, define = "$", ".byte", "%s_%s = %d\n"
+	}
+	fmt.Fprintf(buf, "; %s\n", codeHeader)
+	fmt.Fprintf(buf, "; %dx%d font, %d characters of %d bytes, %d bytes per row.\n\n", f.Width, f.Height, CharsetSz, f.Height*f.stride(), f.stride())
+	fmt.Fprintf(buf, define, macro, "WIDTH", f.Width)
+	fmt.Fprintf(buf, define, macro, "HEIGHT", f.Height)
+	fmt.Fprintf(buf, define, macro, "STRIDE", f.stride())
+	fmt.Fprintf(buf, "\n%s_chars:\n", opts.Name)
+	for ch, data := range f.Chars {
+		fmt.Fprintf(buf, "\t%s %s\t; %s%02x\n", db, hexBytes(data, prefix), prefix, ch)
+	}
+	if len(f.Metrics) > 0 {
+		var left, adv = make([]byte, len(f.Metrics)), make([]byte, len(f.Metrics))
+		for i, m := range f.Metrics {
+			left[i], adv[i] = byte(m.Left), byte(m.Advance)
+		}
+		fmt.Fprintf(buf, "\n%s_left:\n", opts.Name)
+		writeAsmRows(buf, db, prefix, left)
+		fmt.Fprintf(buf, "\n%s_advance:\n", opts.Name)
+		writeAsmRows(buf, db, prefix, adv)
+	}
+	if len(f.Kerning) > 0 {
+		// the negative adjustments are written as the two's complement bytes,
+		// as not every assembler accepts the negative bytes.
+		fmt.Fprintf(buf, "\n")
+		fmt.Fprintf(buf, define, macro, "KERNING_COUNT", len(f.Kerning))
+		fmt.Fprintf(buf, "%s_kerning: ; first, second, adjustment\n", opts.Name)
+		for _, p := range f.kernPairs() {
+			fmt.Fprintf(buf, "\t%s %s\t; %d\n", db, hexBytes([]byte{p[0], p[1], byte(int8(f.Kerning[p]))}, prefix), f.Kerning[p])
+		}
+	}
+}
+
+// writeAsmRows writes the data, 16 bytes per line.
+func writeAsmRows(buf *bytes.Buffer, db, prefix string, data []byte) {
+	for chunk := range slices.Chunk(data, 16) {
+		fmt.Fprintf(buf, "\t%s %s\n", db, hexBytes(chunk, prefix))
+	}
+}
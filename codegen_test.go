@@ -0,0 +1,95 @@
+package fontpic
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func TestFNT_WriteCode(t *testing.T) {
+	fnt := testFont().Proportional(2, 1).WithKerning(Kerning{{'I', 'I'}: -1})
+	tests := []struct {
+		name string
+		opts CodeOptions
+		want []string
+	}{
+		{
+			"go",
+			CodeOptions{Format: CodeGo, Name: "Test", Package: "fonts"},
+			[]string{
+				"// Code generated by fontpic; DO NOT EDIT.\n",
+				"package fonts\n",
+				"var Test = &fontpic.FNT{\n",
+				"\t\t{0x04, 0x04, 0x04, 0x04}, // 0x49\n",
+				"\t\t{Left: 1, Advance: 2}, // 0x49\n",
+				"\t\t{0x49, 0x49}: -1,\n",
+				"var TestFace font.Face = Test.Face()\n",
+			},
+		},
+		{
+			"c",
+			CodeOptions{Format: CodeC, Name: "test"},
+			[]string{
+				"#ifndef TEST_H\n",
+				"#define TEST_WIDTH 4\n",
+				"static const uint8_t test_chars[256][4] = {\n",
+				"\t{0x04, 0x04, 0x04, 0x04}, /* 0x49 */\n",
+				"static const uint8_t test_advance[256] = {\n",
+				"#define TEST_KERNING_COUNT 1\n",
+				"\t{0x49, 0x49, -1},\n",
+				"#endif /* TEST_H */\n",
+			},
+		},
+		{
+			"nasm",
+			CodeOptions{Format: CodeNASM, Name: "test"},
+			[]string{
+				"TEST_HEIGHT equ 4\n",
+				"test_chars:\n",
+				"\tdb 0x04, 0x04, 0x04, 0x04\t; 0x49\n",
+				"test_left:\n",
+				"\tdb 0x49, 0x49, 0xff\t; -1\n",
+			},
+		},
+		{
+			"ca65",
+			CodeOptions{Format: CodeCA65, Name: "test"},
+			[]string{
+				"TEST_HEIGHT = 4\n",
+				"\t.byte $04, $04, $04, $04\t; $49\n",
+				"TEST_KERNING_COUNT = 1\n",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := fnt.WriteCode(&buf, tt.opts); err != nil {
+				t.Fatalf("WriteCode() error = %v", err)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(buf.String(), w) {
+					t.Errorf("output does not contain %q", w)
+				}
+			}
+			if tt.opts.Format == CodeGo {
+				if _, err := parser.ParseFile(token.NewFileSet(), "font.go", buf.Bytes(), 0); err != nil {
+					t.Errorf("invalid Go source: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestParseCodeFormat(t *testing.T) {
+	for _, c := range []CodeFormat{CodeGo, CodeC, CodeNASM, CodeCA65} {
+		if got, err := ParseCodeFormat(strings.ToUpper(c.String())); err != nil || got != c {
+			t.Errorf("ParseCodeFormat(%q) = %v, %v", c, got, err)
+		}
+	}
+	if _, err := ParseCodeFormat("cobol"); err == nil {
+		t.Error("ParseCodeFormat(cobol) expected error")
+	}
+}
@@ -0,0 +1,65 @@
+package fontpic
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+// color.go contains the colour helpers of the command line tools and the
+// server.
+
+// PaletteCGA is the 16 colour palette of the CGA and EGA text modes, in the
+// order of the colour attributes.
+var PaletteCGA = color.Palette{
+	color.RGBA{0x00, 0x00, 0x00, 0xff}, // black
+	color.RGBA{0x00, 0x00, 0xaa, 0xff}, // blue
+	color.RGBA{0x00, 0xaa, 0x00, 0xff}, // green
+	color.RGBA{0x00, 0xaa, 0xaa, 0xff}, // cyan
+	color.RGBA{0xaa, 0x00, 0x00, 0xff}, // red
+	color.RGBA{0xaa, 0x00, 0xaa, 0xff}, // magenta
+	color.RGBA{0xaa, 0x55, 0x00, 0xff}, // brown
+	color.RGBA{0xaa, 0xaa, 0xaa, 0xff}, // light grey
+	color.RGBA{0x55, 0x55, 0x55, 0xff}, // dark grey
+	color.RGBA{0x55, 0x55, 0xff, 0xff}, // light blue
+	color.RGBA{0x55, 0xff, 0x55, 0xff}, // light green
+	color.RGBA{0x55, 0xff, 0xff, 0xff}, // light cyan
+	color.RGBA{0xff, 0x55, 0x55, 0xff}, // light red
+	color.RGBA{0xff, 0x55, 0xff, 0xff}, // light magenta
+	color.RGBA{0xff, 0xff, 0x55, 0xff}, // yellow
+	color.RGBA{0xff, 0xff, 0xff, 0xff}, // white
+}
+
+// colorNames are the names of the PaletteCGA colours.
+var colorNames = []string{
+	"black", "blue", "green", "cyan", "red", "magenta", "brown", "lightgrey",
+	"darkgrey", "lightblue", "lightgreen", "lightcyan", "lightred", "lightmagenta", "yellow", "white",
+}
+
+// ParseColor parses the colour in the hex notation, "#rgb", "#rrggbb" or
+// "#rrggbbaa", with or without the "#", or the name of the CGA colour, i.e.
+// "lightcyan" (both "grey" and "gray" are accepted), or "transparent".
+func ParseColor(s string) (color.Color, error) {
+	name := strings.ReplaceAll(strings.ToLower(s), "gray", "grey")
+	if name == "transparent" {
+		return color.Transparent, nil
+	}
+	for i, n := range colorNames {
+		if name == n {
+			return PaletteCGA[i], nil
+		}
+	}
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if len(hex) != 8 || err != nil {
+		return nil, fmt.Errorf("invalid colour: %q", s)
+	}
+	return color.NRGBA{uint8(v >> 24), uint8(v >> 16), uint8(v >> 8), uint8(v)}, nil
+}
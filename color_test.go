@@ -0,0 +1,36 @@
+package fontpic
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestParseColor(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    color.Color
+		wantErr bool
+	}{
+		{"#ff8000", color.RGBA{0xff, 0x80, 0x00, 0xff}, false},
+		{"FF8000", color.RGBA{0xff, 0x80, 0x00, 0xff}, false},
+		{"#f80", color.RGBA{0xff, 0x88, 0x00, 0xff}, false},
+		{"#ff800080", color.NRGBA{0xff, 0x80, 0x00, 0x80}, false},
+		{"LightCyan", color.RGBA{0x55, 0xff, 0xff, 0xff}, false},
+		{"lightgray", color.RGBA{0xaa, 0xaa, 0xaa, 0xff}, false},
+		{"transparent", color.Transparent, false},
+		{"#ff80", nil, true},
+		{"#gg8000", nil, true},
+		{"mauve", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			got, err := ParseColor(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseColor() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !colEq(got, tt.want) {
+				t.Errorf("ParseColor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package fontpic
+
+import (
+	"errors"
+	"fmt"
+)
+
+// compose.go contains the composition of the fonts from the glyph ranges of
+// several source fonts.
+
+// GlyphRange is the range of characters of the source font.
+type GlyphRange struct {
+	Source *FNT
+	// First and Last are the first and the last characters of the range in
+	// the source font.
+	First, Last byte
+	// At is the code of the First character in the composed font.
+	At byte
+}
+
+// Range returns the range of the characters from first to last of the font,
+// placed at the same codes.
+func (f *FNT) Range(first, last byte) GlyphRange {
+	return GlyphRange{Source: f, First: first, Last: last, At: first}
+}
+
+// Range returns the range of the characters from first to last of the font,
+// placed at the same codes.  The font is converted with [ImageFont.ToFnt].
+func (f *ImageFont) Range(first, last byte) GlyphRange {
+	return f.ToFnt().Range(first, last)
+}
+
+// ComposeOptions are the options of [Compose].
+type ComposeOptions struct {
+	// Width and Height are the cell size of the composed font.  If zero, the
+	// width is the widest of the sources, and the height fits the tallest
+	// ascent and the deepest descent of the sources.
+	Width, Height int
+	// Charset is the charset of the composed font.
+	Charset string
+}
+
+// Conflict is the character of the composed font, that is defined by more
+// than one range.
+type Conflict struct {
+	Char byte
+	// Ranges are the indexes of the ranges, that define the character.  The
+	// last one wins.
+	Ranges []int
+}
+
+func (c Conflict) String() string {
+	return fmt.Sprintf("character 0x%02x is defined by the ranges %v", c.Char, c.Ranges)
+}
+
+// Compose builds the new font from the glyph ranges of the source fonts.  The
+// ranges are applied in order, so the later ranges patch the earlier ones,
+// and every character that is defined more than once is reported as the
+// conflict.  The characters not covered by any range are blank.
+//
+// The glyphs are aligned on the common baseline, so the sources of different
+// height are padded at the top and the bottom, and the narrower glyphs are
+// centred in the cell.  If any of the sources is proportional, the composed
+// font is proportional too.  The kerning pairs are kept, if both characters
+// come from the same source.
+func Compose(opts ComposeOptions, ranges ...GlyphRange) (*FNT, []Conflict, error) {
+	if len(ranges) == 0 {
+		return nil, nil, errors.New("no ranges")
+	}
+	var asc, desc, width int
+	for i, r := range ranges {
+		if r.Source == nil {
+			return nil, nil, fmt.Errorf("range %d: no source font", i)
+		}
+		if r.Last < r.First || int(r.At)+int(r.Last-r.First) >= CharsetSz {
+			return nil, nil, fmt.Errorf("range %d: invalid range 0x%02x-0x%02x at 0x%02x", i, r.First, r.Last, r.At)
+		}
+		asc = max(asc, r.Source.Height-r.Source.descent())
+		desc = max(desc, r.Source.descent())
+		width = max(width, r.Source.Width)
+	}
+	if opts.Width == 0 {
+		opts.Width = width
+	}
+	if opts.Height == 0 {
+		opts.Height = asc + desc
+	}
+	// baseline is the first row below the glyph body in the composed font.
+	baseline := asc + (opts.Height-asc-desc)/2
+	for i, r := range ranges {
+		src := r.Source
+		if src.Width > opts.Width || src.Height-src.descent() > baseline || src.descent() > opts.Height-baseline {
+			return nil, nil, fmt.Errorf("range %d: the %dx%d font does not fit the %dx%d cell", i, src.Width, src.Height, opts.Width, opts.Height)
+		}
+	}
+
+	// owner is the index of the range, that defines each character.
+	var (
+		owner   [CharsetSz]int
+		defined = make(map[byte][]int)
+	)
+	for i := range owner {
+		owner[i] = -1
+	}
+	for i, r := range ranges {
+		for ch := int(r.First); ch <= int(r.Last); ch++ {
+			dst := r.At + byte(ch-int(r.First))
+			owner[dst] = i
+			defined[dst] = append(defined[dst], i)
+		}
+	}
+	var conflicts []Conflict
+	for ch := range CharsetSz {
+		if rr := defined[byte(ch)]; len(rr) > 1 {
+			conflicts = append(conflicts, Conflict{Char: byte(ch), Ranges: rr})
+		}
+	}
+
+	// offset returns the offset of the glyphs of the range in the cell.
+	offset := func(r GlyphRange) (dx, dy int) {
+		return (opts.Width - r.Source.Width) / 2, baseline - (r.Source.Height - r.Source.descent())
+	}
+	// source returns the source character of the composed character ch.
+	source := func(r GlyphRange, ch byte) byte {
+		return r.First + (ch - r.At)
+	}
+	nf := &FNT{
+		Width:   opts.Width,
+		Height:  opts.Height,
+		Charset: opts.Charset,
+		Chars:   toChars(make([]byte, CharsetSz*charStride(opts.Width)*opts.Height), opts.Width, opts.Height),
+	}
+	for ch := range CharsetSz {
+		if owner[ch] < 0 {
+			continue
+		}
+		r := ranges[owner[ch]]
+		dx, dy := offset(r)
+		for y := range r.Source.Height {
+			for x := range r.Source.Width {
+				if r.Source.Pixel(source(r, byte(ch)), x, y) {
+					nf.SetPixel(byte(ch), x+dx, y+dy, true)
+				}
+			}
+		}
+	}
+
+	var proportional bool
+	for _, r := range ranges {
+		proportional = proportional || len(r.Source.Metrics) == CharsetSz
+	}
+	if proportional {
+		nf.Metrics = make([]GlyphMetrics, CharsetSz)
+		for ch := range nf.Metrics {
+			nf.Metrics[ch] = GlyphMetrics{Left: 0, Advance: opts.Width}
+			if owner[ch] < 0 {
+				continue
+			}
+			r := ranges[owner[ch]]
+			dx, _ := offset(r)
+			left, adv := r.Source.hmetrics(source(r, byte(ch)))
+			nf.Metrics[ch] = GlyphMetrics{Left: left + dx, Advance: adv}
+		}
+	}
+
+	for i, r := range ranges {
+		for p, v := range r.Source.Kerning {
+			a, b := int(p[0])-int(r.First)+int(r.At), int(p[1])-int(r.First)+int(r.At)
+			if p[0] < r.First || p[0] > r.Last || p[1] < r.First || p[1] > r.Last || owner[a] != i || owner[b] != i {
+				continue
+			}
+			if nf.Kerning == nil {
+				nf.Kerning = make(Kerning)
+			}
+			nf.Kerning[KernPair{byte(a), byte(b)}] = v
+		}
+	}
+	return nf, conflicts, nil
+}
@@ -0,0 +1,132 @@
+package fontpic
+
+import (
+	"image"
+	"reflect"
+	"testing"
+)
+
+func TestCompose(t *testing.T) {
+	tests := []struct {
+		name          string
+		opts          ComposeOptions
+		ranges        []GlyphRange
+		wantSize      image.Point
+		wantGlyphs    map[byte]string
+		wantConflicts []Conflict
+		wantErr       bool
+	}{
+		{
+			"patch",
+			ComposeOptions{},
+			[]GlyphRange{testFont().Range('A', 'Z'), lFont().Range('L', 'L')},
+			image.Pt(4, 4),
+			map[byte]string{
+				'I': ".#..\n.#..\n.#..\n.#..\n",
+				// aligned on the baseline, the last row is the descent.
+				'L': "....\n....\n#...\n###.\n",
+			},
+			[]Conflict{{Char: 'L', Ranges: []int{0, 1}}},
+			false,
+		},
+		{
+			"moved and padded",
+			ComposeOptions{Width: 5, Height: 6},
+			[]GlyphRange{{Source: lFont(), First: 'L', Last: 'L', At: 0x80}},
+			image.Pt(5, 6),
+			map[byte]string{
+				0x80: ".....\n.....\n.#...\n.###.\n.....\n.....\n",
+				'L':  ".....\n.....\n.....\n.....\n.....\n.....\n",
+			},
+			nil,
+			false,
+		},
+		{
+			"does not fit",
+			ComposeOptions{Width: 2},
+			[]GlyphRange{testFont().Range('A', 'Z')},
+			image.Point{},
+			nil,
+			nil,
+			true,
+		},
+		{
+			"invalid range",
+			ComposeOptions{},
+			[]GlyphRange{{Source: testFont(), First: 0x10, Last: 0x20, At: 0xf0}},
+			image.Point{},
+			nil,
+			nil,
+			true,
+		},
+		{
+			"no ranges",
+			ComposeOptions{},
+			nil,
+			image.Point{},
+			nil,
+			nil,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, conflicts, err := Compose(tt.opts, tt.ranges...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Compose() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if size := image.Pt(got.Width, got.Height); size != tt.wantSize {
+				t.Errorf("size = %v, want %v", size, tt.wantSize)
+			}
+			for ch, want := range tt.wantGlyphs {
+				if g := glyphString(got, ch); g != want {
+					t.Errorf("glyph 0x%02x =\n%s\nwant\n%s", ch, g, want)
+				}
+			}
+			if !reflect.DeepEqual(conflicts, tt.wantConflicts) {
+				t.Errorf("conflicts = %v, want %v", conflicts, tt.wantConflicts)
+			}
+		})
+	}
+}
+
+func TestCompose_metrics(t *testing.T) {
+	prop := testFont().Proportional(2, 1).WithKerning(Kerning{{'I', 'I'}: -1, {'I', 'J'}: 1})
+	got, _, err := Compose(ComposeOptions{Width: 6},
+		GlyphRange{Source: prop, First: 'I', Last: 'I', At: 'i'},
+		lFont().Range('L', 'L'),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the 4 pixel wide glyphs are centred in the 6 pixel cell.
+	if want := (GlyphMetrics{Left: 2, Advance: 2}); got.Metrics['i'] != want {
+		t.Errorf("metrics 'i' = %+v, want %+v", got.Metrics['i'], want)
+	}
+	if want := (GlyphMetrics{Left: 1, Advance: 3}); got.Metrics['L'] != want {
+		t.Errorf("metrics 'L' = %+v, want %+v", got.Metrics['L'], want)
+	}
+	// the pair with 'J' is outside of the range.
+	if want := (Kerning{{'i', 'i'}: -1}); !reflect.DeepEqual(got.Kerning, want) {
+		t.Errorf("kerning = %v, want %v", got.Kerning, want)
+	}
+}
+
+func TestCompose_imageFont(t *testing.T) {
+	got, conflicts, err := Compose(ComposeOptions{Charset: "866"},
+		Fnt8x8.Range(0x00, 0xff),
+		IFMicrofont.Range('0', '9'),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Width != 8 || got.Height != 8 {
+		t.Errorf("size = %dx%d, want 8x8", got.Width, got.Height)
+	}
+	if len(conflicts) != 10 {
+		t.Errorf("conflicts = %d, want 10", len(conflicts))
+	}
+}
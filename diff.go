@@ -0,0 +1,84 @@
+package fontpic
+
+import (
+	"image"
+	"image/color"
+)
+
+// diff.go contains the comparison of the fonts.
+
+// GlyphDiff is the difference of the character between two fonts.
+type GlyphDiff struct {
+	Char byte
+	// Added is the number of pixels set only in the second font.
+	Added int
+	// Removed is the number of pixels set only in the first font.
+	Removed int
+}
+
+// Diff compares the fonts a and b glyph by glyph and returns the characters
+// that differ, in the order of the codes.  The fonts of different sizes are
+// compared in the area of the larger one, with the characters aligned at the
+// top left corner.
+func Diff(a, b *FNT) []GlyphDiff {
+	var (
+		w, h  = max(a.Width, b.Width), max(a.Height, b.Height)
+		diffs []GlyphDiff
+	)
+	for ch := range CharsetSz {
+		d := GlyphDiff{Char: byte(ch)}
+		for y := range h {
+			for x := range w {
+				pa, pb := a.Pixel(byte(ch), x, y), b.Pixel(byte(ch), x, y)
+				switch {
+				case pb && !pa:
+					d.Added++
+				case pa && !pb:
+					d.Removed++
+				}
+			}
+		}
+		if d.Added > 0 || d.Removed > 0 {
+			diffs = append(diffs, d)
+		}
+	}
+	return diffs
+}
+
+var (
+	// DiffAddedColor is the colour of the pixels added in the diff sheet.
+	DiffAddedColor color.Color = color.RGBA{0x55, 0xff, 0x55, 0xff}
+	// DiffRemovedColor is the colour of the pixels removed in the diff sheet.
+	DiffRemovedColor color.Color = color.RGBA{0xff, 0x55, 0x55, 0xff}
+)
+
+// DiffSheet renders the visual diff of the fonts a and b in the same grid as
+// [FNT.Sample].  The pixels, that are set in both fonts, are grey, the pixels
+// added in b are DiffAddedColor, and the pixels removed from a are
+// DiffRemovedColor.  The perLine is clamped the same way as in Sample.
+func DiffSheet(a, b *FNT, perLine int) image.Image {
+	var (
+		cell    = image.Pt(max(a.Width, b.Width), max(a.Height, b.Height))
+		spacing = image.Point{1, 1}
+		img     = image.NewRGBA(image.Rectangle{Max: sampleSize(perLine, cell, spacing)})
+		same    = color.Gray{0xa8}
+	)
+	fill(img, color.Black)
+	for ch := range CharsetSz {
+		at := sampleAt(perLine, ch, cell, spacing)
+		for y := range cell.Y {
+			for x := range cell.X {
+				pa, pb := a.Pixel(byte(ch), x, y), b.Pixel(byte(ch), x, y)
+				switch {
+				case pa && pb:
+					img.Set(at.X+x, at.Y+y, same)
+				case pb:
+					img.Set(at.X+x, at.Y+y, DiffAddedColor)
+				case pa:
+					img.Set(at.X+x, at.Y+y, DiffRemovedColor)
+				}
+			}
+		}
+	}
+	return img
+}
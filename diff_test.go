@@ -0,0 +1,91 @@
+package fontpic
+
+import (
+	"image"
+	"image/color"
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b *FNT
+		want []GlyphDiff
+	}{
+		{
+			"same",
+			testFont(),
+			testFont(),
+			nil,
+		},
+		{
+			"bold",
+			testFont(),
+			testFont().Bold(1),
+			[]GlyphDiff{{Char: 'I', Added: 4}},
+		},
+		{
+			"flipped",
+			testFont(),
+			testFont().FlipH(),
+			[]GlyphDiff{{Char: 'I', Added: 4, Removed: 4}},
+		},
+		{
+			"different size",
+			lFont(),
+			testFont(),
+			[]GlyphDiff{{Char: 'I', Added: 4}, {Char: 'L', Removed: 4}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Diff(tt.a, tt.b); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffSheet(t *testing.T) {
+	img := DiffSheet(testFont(), testFont().FlipH(), 16)
+	if got, want := img.Bounds(), testFont().Sample(16).Bounds(); got != want {
+		t.Fatalf("bounds = %v, want %v", got, want)
+	}
+	// 'I' is in the row 4 and the column 9 of 5x5 cells.
+	at := image.Pt(9*5, 4*5)
+	tests := []struct {
+		x    int
+		want color.Color
+	}{
+		{0, color.Black},
+		{1, DiffRemovedColor},
+		{2, DiffAddedColor},
+		{3, color.Black},
+	}
+	for _, tt := range tests {
+		if got := img.At(at.X+tt.x, at.Y); !colEq(got, tt.want) {
+			t.Errorf("pixel %d = %v, want %v", tt.x, got, tt.want)
+		}
+	}
+}
+
+func TestDiffSheet_perLine(t *testing.T) {
+	tests := []struct {
+		name    string
+		perLine int
+		want    image.Point
+	}{
+		{"zero", 0, image.Pt(5, 256*5)},
+		{"negative", -3, image.Pt(5, 256*5)},
+		{"not a divisor", 10, image.Pt(10*5, 26*5)},
+		{"too many", 1000, image.Pt(256*5, 5)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DiffSheet(testFont(), testFont(), tt.perLine).Bounds().Size(); got != tt.want {
+				t.Errorf("size = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package fontpic
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// effects.go contains the legibility effects for the text rendered over
+// busy backgrounds.
+
+// Effects are the effects drawn under the text: outline, drop shadow and glow.
+// They are computed from the coverage of the glyph bitmaps, so the text is
+// rendered only once.  Zero value means no effects.
+type Effects struct {
+	// Outline is the width of the outline in pixels.
+	Outline      int
+	OutlineColor color.Color
+	// Shadow is the offset of the drop shadow.  The shadow is cast by the
+	// text together with the outline.
+	Shadow      image.Point
+	ShadowColor color.Color
+	// Glow is the radius of the glow in pixels.  The glow fades out linearly.
+	Glow      int
+	GlowColor color.Color
+}
+
+func (e Effects) enabled() bool {
+	return e.Outline > 0 || e.Shadow != (image.Point{}) || e.Glow > 0
+}
+
+// margin returns the amount of pixels that the effects extend beyond the
+// text on each side.
+func (e Effects) margin() (lt, rb image.Point) {
+	ext := max(e.Outline, e.Glow)
+	lt = image.Point{ext + max(0, -e.Shadow.X), ext + max(0, -e.Shadow.Y)}
+	rb = image.Point{ext + max(0, e.Shadow.X), ext + max(0, e.Shadow.Y)}
+	return lt, rb
+}
+
+// draw draws the effects and the text, defined by the coverage mask, to dst
+// using the fg color for the text.
+func (e Effects) draw(dst draw.Image, mask *image.Alpha, fg color.Color) {
+	b := mask.Bounds()
+	dist := distanceMap(mask, max(e.Outline, e.Glow))
+	if e.Glow > 0 {
+		glow := image.NewAlpha(b)
+		for i, d := range dist {
+			if d <= e.Glow {
+				glow.Pix[i] = uint8(0xff * (e.Glow + 1 - d) / (e.Glow + 1))
+			}
+		}
+		drawMask(dst, glow, image.Point{}, colorOr(e.GlowColor, color.White))
+	}
+	// the shape of the text with the outline.
+	shape := mask
+	if e.Outline > 0 {
+		shape = image.NewAlpha(b)
+		for i, d := range dist {
+			if d <= e.Outline {
+				shape.Pix[i] = 0xff
+			}
+		}
+	}
+	if e.Shadow != (image.Point{}) {
+		drawMask(dst, shape, e.Shadow, colorOr(e.ShadowColor, color.Black))
+	}
+	if e.Outline > 0 {
+		drawMask(dst, shape, image.Point{}, colorOr(e.OutlineColor, color.Black))
+	}
+	drawMask(dst, mask, image.Point{}, fg)
+}
+
+// drawMask paints the dst with col through the mask, shifted by the offset.
+func drawMask(dst draw.Image, mask *image.Alpha, offset image.Point, col color.Color) {
+	r := mask.Bounds().Add(offset)
+	draw.DrawMask(dst, r, image.NewUniform(col), image.Point{}, mask, mask.Bounds().Min, draw.Over)
+}
+
+func colorOr(c, def color.Color) color.Color {
+	if c == nil {
+		return def
+	}
+	return c
+}
+
+// distanceMap returns the chessboard distance from each pixel of the mask to
+// the nearest pixel that is set, up to the limit.  Pixels further away than
+// the limit have the distance of limit+1.  The distances are indexed the same
+// way as mask.Pix.
+func distanceMap(mask *image.Alpha, limit int) []int {
+	var (
+		b    = mask.Bounds()
+		w, h = b.Dx(), b.Dy()
+		dist = make([]int, len(mask.Pix))
+	)
+	for i := range dist {
+		if mask.Pix[i] != 0 {
+			dist[i] = 0
+		} else {
+			dist[i] = limit + 1
+		}
+	}
+	// each pass dilates the set pixels by one pixel in all directions.
+	for d := 1; d <= limit; d++ {
+		for y := range h {
+			for x := range w {
+				i := y*mask.Stride + x
+				if dist[i] <= d {
+					continue
+				}
+			neighbours:
+				for dy := -1; dy <= 1; dy++ {
+					for dx := -1; dx <= 1; dx++ {
+						nx, ny := x+dx, y+dy
+						if nx < 0 || ny < 0 || nx >= w || ny >= h {
+							continue
+						}
+						if dist[ny*mask.Stride+nx] == d-1 {
+							dist[i] = d
+							break neighbours
+						}
+					}
+				}
+			}
+		}
+	}
+	return dist
+}
+
+// WithOutline sets the outline of the text of the given width and color.
+func (c *Canvas) WithOutline(width int, col color.Color) *Canvas {
+	c.Effects.Outline = width
+	c.Effects.OutlineColor = col
+	return c
+}
+
+// WithShadow sets the drop shadow with the given offset and color.
+func (c *Canvas) WithShadow(offset image.Point, col color.Color) *Canvas {
+	c.Effects.Shadow = offset
+	c.Effects.ShadowColor = col
+	return c
+}
+
+// WithGlow sets the glow of the given radius and color.
+func (c *Canvas) WithGlow(radius int, col color.Color) *Canvas {
+	c.Effects.Glow = radius
+	c.Effects.GlowColor = col
+	return c
+}
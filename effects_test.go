@@ -0,0 +1,70 @@
+package fontpic
+
+import (
+	"image"
+	"image/color"
+	"reflect"
+	"testing"
+)
+
+func Test_distanceMap(t *testing.T) {
+	mask := image.NewAlpha(image.Rect(0, 0, 5, 1))
+	mask.Pix[0] = 0xff
+	want := []int{0, 1, 2, 3, 3}
+	if got := distanceMap(mask, 2); !reflect.DeepEqual(got, want) {
+		t.Errorf("distanceMap() = %v, want %v", got, want)
+	}
+}
+
+func TestCanvas_effects(t *testing.T) {
+	var (
+		fg      = color.RGBA{0xff, 0xff, 0xff, 0xff}
+		bg      = color.RGBA{0, 0, 0xff, 0xff}
+		outline = color.RGBA{0xff, 0, 0, 0xff}
+		shadow  = color.RGBA{0, 0xff, 0, 0xff}
+	)
+	tests := []struct {
+		name     string
+		canvas   *Canvas
+		wantSize image.Point
+		pixels   map[image.Point]color.Color
+	}{
+		{
+			name:     "outline",
+			canvas:   NewCanvas(testFont()).WithOutline(1, outline),
+			wantSize: image.Pt(4+2, 4+2),
+			pixels: map[image.Point]color.Color{
+				{0, 0}: bg, // the glyph is in the second column, shifted by 1
+				{1, 0}: outline,
+				{1, 1}: outline,
+				{2, 1}: fg,
+				{2, 4}: fg,
+				{4, 2}: bg,
+			},
+		},
+		{
+			name:     "shadow",
+			canvas:   NewCanvas(testFont()).WithShadow(image.Pt(1, 1), shadow),
+			wantSize: image.Pt(4+1, 4+1),
+			pixels: map[image.Point]color.Color{
+				{1, 0}: fg,
+				{2, 0}: bg,
+				{2, 1}: shadow,
+				{2, 4}: shadow,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img := tt.canvas.WithForeground(fg).WithBackground(bg).RenderText([]byte("I")).Image()
+			if got := img.Bounds().Size(); got != tt.wantSize {
+				t.Errorf("size = %v, want %v", got, tt.wantSize)
+			}
+			for pt, want := range tt.pixels {
+				if got := img.At(pt.X, pt.Y); !colEq(got, want) {
+					t.Errorf("pixel %v = %v, want %v", pt, got, want)
+				}
+			}
+		})
+	}
+}
@@ -2,9 +2,18 @@ package fontpic
 
 import (
 	"image"
+	"image/color"
 	"math/bits"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"unicode/utf8"
 
+	"golang.org/x/image/font"
 	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+
+	"github.com/rusq/fontpic/charset"
 )
 
 // face.go contains compatibility code for the font package.
@@ -14,143 +23,186 @@ const (
 )
 
 var (
+	// The faces build the glyph mask on the first use, so that the unused
+	// faces cost nothing at the start of the program.
+
 	// Face8x8 is the Keyrus 8x8 face.
-	Face8x8 = FntToFace(fntKr8x8, 8, 8)
+	Face8x8 = lazyFace(Fnt8x8.faceMetrics(), Fnt8x8.maskRect(), loadFace8x8)
 	// Face8x14 is the Keyrus 8x14 font face.
-	Face8x14 = FntToFace(fntKr8x14, 8, 14)
+	Face8x14 = lazyFace(Fnt8x14.faceMetrics(), Fnt8x14.maskRect(), loadFace8x14)
 	// Face8x16 is the Keyrus 8x16 font face.
-	Face8x16 = FntToFace(fntKr8x16, 8, 16)
+	Face8x16 = lazyFace(Fnt8x16.faceMetrics(), Fnt8x16.maskRect(), loadFace8x16)
 
-	// Face4x4 is Microfont 4x4 font face.
-	Face4x4 = &basicfont.Face{
-		Advance: 5,
-		Width:   8,
-		Height:  5,
-		Ascent:  4,
-		Descent: 1,
-		Left:    -4,
-		Mask: &image.Alpha{
-			Pix:    Bytes2pixels(fntMicrofont[0x20*5:]),
-			Stride: 8,
-			Rect:   image.Rectangle{Max: image.Point{8, 96 * 8}},
-		},
-		Ranges: basicfont.Face7x13.Ranges,
-	}
+	// Faces of the image fonts decode the font images on the first use of
+	// the glyph mask.  The mask of the font, that fails to load, is blank,
+	// use the Load functions, i.e. [LoadFace4x4], to get the error.
 
+	// Face4x4 is Microfont 4x4 font face.
+	Face4x4 = imageLazyFace(&IFMicrofont, loadFace4x4)
 	// Face4x4Bold is Microfont Bold 4x4 font face.
-	Face4x4Bold = &basicfont.Face{
-		Advance: 5,
-		Width:   8,
-		Height:  5,
-		Ascent:  4,
-		Descent: 1,
-		Left:    -4,
-		Mask: &image.Alpha{
-			Pix:    Bytes2pixels(fntMicrofontBold[0x20*5:]),
-			Stride: 8,
-			Rect:   image.Rectangle{Max: image.Point{8, 96 * 8}},
-		},
-		Ranges: basicfont.Face7x13.Ranges,
-	}
-
+	Face4x4Bold = imageLazyFace(&IFMicrofontBold, loadFace4x4Bold)
 	// Face4x4Italic is Microfont Italic 4x4 font face.
-	Face4x4Italic = &basicfont.Face{
-		Advance: 5,
-		Width:   8,
-		Height:  5,
-		Ascent:  4,
-		Descent: 1,
-		Left:    -4,
-		Mask: &image.Alpha{
-			Pix:    Bytes2pixels(fntMicrofontItalic[0x20*5:]),
-			Stride: 8,
-			Rect:   image.Rectangle{Max: image.Point{8, 96 * 8}},
-		},
-		Ranges: basicfont.Face7x13.Ranges,
-	}
-
+	Face4x4Italic = imageLazyFace(&IFMicrofontItalic, loadFace4x4Italic)
 	// Face4x5 is the Millifont 5x4 font face.
-	Face4x5 = &basicfont.Face{
-		Advance: 4,
-		Width:   8,
-		Height:  6,
-		Ascent:  5,
-		Descent: 1,
-		Left:    -4,
-		Mask: &image.Alpha{
-			Pix:    Bytes2pixels(fntMilifont[0x20*6:]),
-			Stride: 8,
-			Rect:   image.Rectangle{Max: image.Point{8, 96 * 8}},
-		},
-		Ranges: basicfont.Face7x13.Ranges,
-	}
-
+	Face4x5 = imageLazyFace(&IFMiliFont, loadFace4x5)
 	// Face6x5 is the Stupid Simple font face.
-	Face6x5 = &basicfont.Face{
-		Advance: 6,
-		Width:   8,
-		Height:  6,
-		Ascent:  5,
-		Descent: 1,
-		Left:    -4,
-		Mask: &image.Alpha{
-			Pix:    Bytes2pixels(fntStupidsimplefont[0x20*6:]),
-			Stride: 8,
-			Rect:   image.Rectangle{Max: image.Point{8, 96 * 8}},
-		},
-		Ranges: basicfont.Face7x13.Ranges,
-	}
-
+	Face6x5 = imageLazyFace(&IFStupidSimple, loadFace6x5)
 	// Face6x5Bold is the Stupid Simple Bold font face.
-	Face6x5Bold = &basicfont.Face{
-		Advance: 6,
-		Width:   8,
-		Height:  6,
-		Ascent:  5,
-		Descent: 1,
-		Left:    -4,
-		Mask: &image.Alpha{
-			Pix:    Bytes2pixels(fntStupidsimplefontBold[0x20*6:]),
-			Stride: 8,
-			Rect:   image.Rectangle{Max: image.Point{8, 96 * 8}},
-		},
-		Ranges: basicfont.Face7x13.Ranges,
-	}
-
+	Face6x5Bold = imageLazyFace(&IFStupidSimpleBold, loadFace6x5Bold)
 	// Face6x5Italic is the Stupid Simple Italic font face.
-	Face6x5Italic = &basicfont.Face{
-		Advance: 6,
-		Width:   8,
-		Height:  6,
-		Ascent:  5,
-		Descent: 1,
-		Left:    -4,
-		Mask: &image.Alpha{
-			Pix:    Bytes2pixels(fntStupidsimplefontItalic[0x20*6:]),
-			Stride: 8,
-			Rect:   image.Rectangle{Max: image.Point{8, 96 * 8}},
-		},
-		Ranges: basicfont.Face7x13.Ranges,
-	}
+	Face6x5Italic = imageLazyFace(&IFStupidSimpleItalic, loadFace6x5Italic)
+
+	// FaceRobotron is the Robotron 9x18 font face, with the characters from
+	// 32 to 203.
+	FaceRobotron = lazyFace(robotronFace(), robotronRect, loadFaceRobotron)
+)
+
+// loaders of the faces, the font is loaded and the face is built on the
+// first call.
+var (
+	loadFace8x8       = builtFace(Fnt8x8.basicFace)
+	loadFace8x14      = builtFace(Fnt8x14.basicFace)
+	loadFace8x16      = builtFace(Fnt8x16.basicFace)
+	loadFaceRobotron  = builtFace(robotronMaskFace)
+	loadFace4x4       = imageFace(&IFMicrofont)
+	loadFace4x4Bold   = imageFace(&IFMicrofontBold)
+	loadFace4x4Italic = imageFace(&IFMicrofontItalic)
+	loadFace4x5       = imageFace(&IFMiliFont)
+	loadFace6x5       = imageFace(&IFStupidSimple)
+	loadFace6x5Bold   = imageFace(&IFStupidSimpleBold)
+	loadFace6x5Italic = imageFace(&IFStupidSimpleItalic)
+)
 
-	FaceRobotron = &basicfont.Face{
+// robotronRect is the bounds of the Robotron glyph mask.
+var robotronRect = image.Rectangle{Max: image.Point{16, 173 * 18}}
+
+// robotronFace returns the Robotron face without the mask.
+func robotronFace() basicfont.Face {
+	return basicfont.Face{
 		Advance: 10,
 		Width:   9,
 		Height:  18,
 		Ascent:  14,
 		Descent: 4,
 		Left:    7,
-		Mask: &image.Alpha{
-			Pix:    Bytes2pixels(uint16ToUint8Rev(everySecond(robotronFnt))),
-			Stride: krStride * 2,
-			Rect:   image.Rectangle{Max: image.Point{16, 173 * 18}},
-		},
 		Ranges: []basicfont.Range{
 			{Low: 32, High: 204, Offset: 0},
 			{Low: '\ufffd', High: '\ufffe', Offset: 1},
 		},
 	}
-)
+}
+
+// robotronMaskFace returns the Robotron face with the glyph mask.
+func robotronMaskFace() *basicfont.Face {
+	face := robotronFace()
+	face.Mask = &image.Alpha{
+		Pix:    Bytes2pixels(uint16ToUint8Rev(everySecond(robotronFnt))),
+		Stride: krStride * 2,
+		Rect:   robotronRect,
+	}
+	return &face
+}
+
+// LoadFaceRobotron returns the Robotron font face with the glyph mask built.
+// The font is compiled in, so unlike [LoadFace4x4], it can not fail.
+func LoadFaceRobotron() *basicfont.Face {
+	face, _ := loadFaceRobotron()
+	return face
+}
+
+// LoadFace4x4 returns the Microfont 4x4 font face with the glyphs loaded, or
+// the error, if the font image can not be decoded.
+func LoadFace4x4() (*basicfont.Face, error) { return loadFace4x4() }
+
+// LoadFace4x4Bold returns the Microfont Bold 4x4 font face, see [LoadFace4x4].
+func LoadFace4x4Bold() (*basicfont.Face, error) { return loadFace4x4Bold() }
+
+// LoadFace4x4Italic returns the Microfont Italic 4x4 font face, see
+// [LoadFace4x4].
+func LoadFace4x4Italic() (*basicfont.Face, error) { return loadFace4x4Italic() }
+
+// LoadFace4x5 returns the Millifont 5x4 font face, see [LoadFace4x4].
+func LoadFace4x5() (*basicfont.Face, error) { return loadFace4x5() }
+
+// LoadFace6x5 returns the Stupid Simple font face, see [LoadFace4x4].
+func LoadFace6x5() (*basicfont.Face, error) { return loadFace6x5() }
+
+// LoadFace6x5Bold returns the Stupid Simple Bold font face, see [LoadFace4x4].
+func LoadFace6x5Bold() (*basicfont.Face, error) { return loadFace6x5Bold() }
+
+// LoadFace6x5Italic returns the Stupid Simple Italic font face, see
+// [LoadFace4x4].
+func LoadFace6x5Italic() (*basicfont.Face, error) { return loadFace6x5Italic() }
+
+// imageFace returns the function, that loads the image font and builds its
+// face on the first call.
+func imageFace(f *ImageFont) func() (*basicfont.Face, error) {
+	return sync.OnceValues(func() (*basicfont.Face, error) {
+		if err := f.Ready(); err != nil {
+			return nil, err
+		}
+		return f.basicFace(), nil
+	})
+}
+
+// builtFace returns the function, that builds the face of the compiled in
+// font on the first call.
+func builtFace(build func() *basicfont.Face) func() (*basicfont.Face, error) {
+	return sync.OnceValues(func() (*basicfont.Face, error) {
+		return build(), nil
+	})
+}
+
+// imageLazyFace returns the face of the image font, that has the metrics of
+// the font and the mask, that calls load on the first use.
+func imageLazyFace(f *ImageFont, load func() (*basicfont.Face, error)) *basicfont.Face {
+	return lazyFace(f.faceMetrics(), f.maskRect(), load)
+}
+
+// lazyFace returns the face with the metrics of face and the mask with the
+// bounds rect, that calls load on the first use.
+func lazyFace(face basicfont.Face, rect image.Rectangle, load func() (*basicfont.Face, error)) *basicfont.Face {
+	face.Mask = &lazyMask{rect: rect, load: load}
+	return &face
+}
+
+// lazyMask is the glyph mask of the face, that is loaded on the first use.
+// Once loaded, the mask is cached, so that drawing the glyph costs a single
+// atomic load per pixel on top of the *image.Alpha lookup.
+type lazyMask struct {
+	rect image.Rectangle
+	load func() (*basicfont.Face, error)
+	mask atomic.Pointer[image.Alpha] // nil until loaded.
+}
+
+// alpha returns the loaded mask, or nil, if the font fails to load.
+func (m *lazyMask) alpha() *image.Alpha {
+	if a := m.mask.Load(); a != nil {
+		return a
+	}
+	face, err := m.load()
+	if err != nil {
+		return nil
+	}
+	a, _ := face.Mask.(*image.Alpha)
+	m.mask.Store(a)
+	return a
+}
+
+func (m *lazyMask) ColorModel() color.Model { return color.AlphaModel }
+
+func (m *lazyMask) Bounds() image.Rectangle { return m.rect }
+
+func (m *lazyMask) At(x, y int) color.Color { return m.AlphaAt(x, y) }
+
+// AlphaAt returns the mask alpha at x, y, it is blank, if the font fails to
+// load.
+func (m *lazyMask) AlphaAt(x, y int) color.Alpha {
+	if a := m.alpha(); a != nil {
+		return a.AlphaAt(x, y)
+	}
+	return color.Alpha{}
+}
 
 const bitsPerByte = 8 // defined as constant to easily update, when this changes
 
@@ -181,36 +233,230 @@ func bits2bytes(b byte) (p [8]byte) {
 
 // FntToFace creates a basicfont.Face from fnt file data.  Width and height are
 // the width and height of the font in pixels.  The data must be a valid fnt
-// file.  It is designed to work with standard 8x8, 8x14 and 8x16 fonts with
-// 256 characters, and might not work correctly with non-standard height and
-// width values. For non-standard fonts, you might want to define your own face
-// based on the basicfont.Face struct, and convert the font data to pixels
-// using [Bytes2pixels] before assigning it to the Mask.Pix field.
+// file with 256 characters.  Runes are mapped to the character codes as is,
+// i.e. 'A' is the character 0x41 and '\u00ff' is the character 0xff.  To map
+// runes through the code page of the font, use [FNT.Face] instead.
 func FntToFace(data []byte, width, height int) *basicfont.Face {
-	var descent = 1
-	if height > 8 {
-		descent = 2
+	fnt := &FNT{
+		Width:  width,
+		Height: height,
+		Chars:  toChars(data, width, height),
 	}
+	return fnt.basicFace()
+}
 
-	return &basicfont.Face{
-		Advance: width,
-		Width:   width - 1,
-		Height:  height,
-		Ascent:  height - descent,
+// Face returns the font.Face for the font.  Runes are translated to the
+// character codes using the font Charset, so that, for example, the Cyrillic
+// text can be drawn with the font.Drawer using the 866 code page fonts.  If the
+// font has no charset, or the charset is unknown, runes are mapped to the
+// character codes as is.  Advances of the proportional fonts and the kerning
+// are honoured.
+func (f *FNT) Face() font.Face {
+	if len(f.Metrics) == CharsetSz || len(f.Kerning) > 0 {
+		return &propFace{Face: f.basicFace(), metrics: f.Metrics, kern: f.Kerning}
+	}
+	return f.basicFace()
+}
+
+// basicFace converts the font to basicfont.Face.
+func (f *FNT) basicFace() *basicfont.Face {
+	face := f.faceMetrics()
+	face.Mask = f.mask()
+	return &face
+}
+
+// faceMetrics returns the face of the font without the mask.
+func (f *FNT) faceMetrics() basicfont.Face {
+	descent := f.descent()
+	return basicfont.Face{
+		Advance: f.Width,
+		Width:   f.Width,
+		Height:  f.Height,
+		Ascent:  f.Height - descent,
+		Descent: descent,
+		Left:    0,
+		Ranges:  f.ranges(),
+	}
+}
+
+// maskRect returns the bounds of the glyph mask of the font.
+func (f *FNT) maskRect() image.Rectangle {
+	return image.Rect(0, 0, f.Width, CharsetSz*f.Height)
+}
+
+// mask returns the alpha mask with all the characters of the font stacked
+// vertically.
+func (f *FNT) mask() *image.Alpha {
+	mask := image.NewAlpha(f.maskRect())
+	for ch := range CharsetSz {
+		for y := range f.Height {
+			for x := range f.Width {
+				if f.Pixel(byte(ch), x, y) {
+					mask.Pix[(ch*f.Height+y)*mask.Stride+x] = 0xff
+				}
+			}
+		}
+	}
+	return mask
+}
+
+// replacementChar is the character that is used for the runes that are not
+// in the font.
+const replacementChar = 1
+
+// ranges returns the basicfont ranges, that map runes to the characters of
+// the font through the font charset.
+func (f *FNT) ranges() []basicfont.Range {
+	cs, ok := charset.ByName(f.Charset)
+	if !ok {
+		return []basicfont.Range{
+			{Low: '\u0000', High: '\u0100', Offset: 0},
+			{Low: '\ufffd', High: '\ufffe', Offset: replacementChar},
+		}
+	}
+	runes := f.runes(cs)
+	if _, ok := runes['\ufffd']; !ok {
+		runes['\ufffd'] = replacementChar
+	}
+	return makeRanges(runes)
+}
+
+// runes returns the map of runes to the character codes in the charset cs.
+// If the charset is empty, the codes are mapped to the runes as is (Latin-1).
+func (f *FNT) runes(cs charset.Charset) map[rune]int {
+	var runes = make(map[rune]int, CharsetSz)
+	for ch := CharsetSz - 1; ch >= 0; ch-- {
+		r := rune(ch)
+		if cs != "" {
+			r = cs.Rune(byte(ch))
+		}
+		if r != utf8.RuneError {
+			runes[r] = ch // lower codes win, if a rune is mapped twice.
+		}
+	}
+	return runes
+}
+
+// makeRanges converts the rune to character index map to the sorted list of
+// basicfont ranges, merging the consecutive runes that map to consecutive
+// characters.
+func makeRanges(runes map[rune]int) []basicfont.Range {
+	var keys = make([]rune, 0, len(runes))
+	for r := range runes {
+		keys = append(keys, r)
+	}
+	slices.Sort(keys)
+	var ranges []basicfont.Range
+	for _, r := range keys {
+		if n := len(ranges); n > 0 {
+			last := &ranges[n-1]
+			if last.High == r && int(r-last.Low)+last.Offset == runes[r] {
+				last.High++
+				continue
+			}
+		}
+		ranges = append(ranges, basicfont.Range{Low: r, High: r + 1, Offset: runes[r]})
+	}
+	return ranges
+}
+
+// Face returns the font.Face for the image font.  The metrics are derived from
+// the image grid: the glyph is GridSize wide and tall, the advance includes the
+// padding between characters, and the padding below the character is used as
+// a descent.  Runes outside of the font range are rendered with the last
+// character of the font.  Advances of the proportional fonts and the kerning
+// are honoured.  The font, that is not ready, is blank, see [ImageFont.Ready].
+func (f *ImageFont) Face() font.Face {
+	f.Ready() // the embedded font is loaded on the first use.
+	if len(f.Metrics) == 0 && len(f.Kerning) == 0 {
+		return f.basicFace()
+	}
+	var metrics []GlyphMetrics
+	for _, m := range f.Metrics {
+		metrics = append(metrics, GlyphMetrics{Left: m.Left, Advance: m.Advance + f.GridPadding})
+	}
+	return &propFace{Face: f.basicFace(), metrics: metrics, kern: f.Kerning, first: f.CharStart}
+}
+
+// basicFace converts the image font to basicfont.Face, ignoring the character
+// metrics.
+func (f *ImageFont) basicFace() *basicfont.Face {
+	var (
+		face   = f.faceMetrics()
+		height = face.Height
+	)
+	mask := image.NewAlpha(f.maskRect())
+	for i := range f.numChars() {
+		src := f.Char(byte(int(f.CharStart) + i))
+		for y := range f.GridSize.Y {
+			for x := range f.GridSize.X {
+				if f.charPixel(src, x, y) {
+					mask.Pix[(i*height+y)*mask.Stride+x] = 0xff
+				}
+			}
+		}
+	}
+	face.Mask = mask
+	return &face
+}
+
+// faceMetrics returns the face of the image font without the mask.
+func (f *ImageFont) faceMetrics() basicfont.Face {
+	descent := f.GridPadding
+	return basicfont.Face{
+		Advance: f.GridSize.X + f.GridPadding,
+		Width:   f.GridSize.X,
+		Height:  f.GridSize.Y + descent,
+		Ascent:  f.GridSize.Y,
 		Descent: descent,
 		Left:    0,
-		Mask: &image.Alpha{
-			Pix:    Bytes2pixels(data),
-			Stride: bitsPerByte,
-			Rect:   image.Rectangle{Max: image.Point{width, 255 * height}},
-		},
 		Ranges: []basicfont.Range{
-			{Low: '\u0000', High: '\u00ff', Offset: 0},
-			{Low: '\ufffd', High: '\ufffe', Offset: 1},
+			{Low: rune(f.CharStart), High: rune(f.CharEnd) + 1, Offset: 0},
+			{Low: '\ufffd', High: '\ufffe', Offset: f.numChars() - 1},
 		},
 	}
 }
 
+// maskRect returns the bounds of the glyph mask of the font face.
+func (f *ImageFont) maskRect() image.Rectangle {
+	return image.Rect(0, 0, f.GridSize.X, f.numChars()*(f.GridSize.Y+f.GridPadding))
+}
+
+// numChars returns the number of characters in the font.
+func (f *ImageFont) numChars() int {
+	return int(f.CharEnd) - int(f.CharStart) + 1
+}
+
+// FaceToFnt converts the basicfont.Face to FNT.  The runes from 0 to 255 are
+// mapped to the character codes as is, and the missing characters are left
+// blank.  This allows to use the faces, such as [FaceRobotron], with the
+// functions that work with FNT.
+func FaceToFnt(face *basicfont.Face) *FNT {
+	var (
+		width  = face.Width
+		height = face.Ascent + face.Descent
+	)
+	fnt := &FNT{
+		Width:  width,
+		Height: height,
+		Chars:  toChars(make([]byte, CharsetSz*charStride(width)*height), width, height),
+	}
+	for ch := range CharsetSz {
+		_, mask, maskp, _, ok := face.Glyph(fixed.P(0, face.Ascent), rune(ch))
+		if !ok {
+			continue
+		}
+		for y := range height {
+			for x := range width {
+				if _, _, _, a := mask.At(maskp.X+x, maskp.Y+y).RGBA(); a >= 0x8000 {
+					fnt.SetPixel(byte(ch), x, y, true)
+				}
+			}
+		}
+	}
+	return fnt
+}
+
 // For example, 0xAABB turns into 0xAA, 0xBB (big-endian).
 func uint16ToUint8(data []uint16) []byte {
 	var ret = make([]byte, len(data)*2)
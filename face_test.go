@@ -1,8 +1,17 @@
 package fontpic
 
 import (
+	"image"
+	"image/color"
+	"image/draw"
 	"reflect"
 	"testing"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+
+	"github.com/rusq/fontpic/charset"
 )
 
 func Test_bits2byte(t *testing.T) {
@@ -106,3 +115,123 @@ func Test_everySecond(t *testing.T) {
 		})
 	}
 }
+
+func TestFNT_Face(t *testing.T) {
+	tests := []struct {
+		name      string
+		fnt       *FNT
+		r         rune
+		wantChar  int
+		wantFound bool
+	}{
+		{"ASCII", Fnt8x16, 'A', 'A', true},
+		{"Cyrillic", Fnt8x16, 'П', 0x8f, true},
+		{"last glyph", Fnt8x16, '\u00a0', 0xff, true},
+		{"box drawing", Fnt8x8, '╬', 0xce, true},
+		{"missing", Fnt8x16, '€', replacementChar, false},
+		{"no charset", &FNT{Width: 8, Height: 8, Chars: toChars(fntKr8x8, 8, 8)}, 'ÿ', 0xff, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			face := tt.fnt.Face()
+			_, _, maskp, advance, ok := face.Glyph(fixed.P(0, 0), tt.r)
+			if ok != tt.wantFound {
+				t.Errorf("Glyph() ok = %v, want %v", ok, tt.wantFound)
+			}
+			if want := tt.wantChar * tt.fnt.Height; maskp.Y != want {
+				t.Errorf("Glyph() maskp.Y = %d, want %d", maskp.Y, want)
+			}
+			if advance != fixed.I(tt.fnt.Width) {
+				t.Errorf("Glyph() advance = %v, want %v", advance, fixed.I(tt.fnt.Width))
+			}
+		})
+	}
+}
+
+func TestFNT_Face_drawString(t *testing.T) {
+	const text = "Привет"
+	want := NewCanvas(Fnt8x16).
+		WithForeground(color.White).
+		WithBackground(color.Black).
+		RenderText(charset.CP866.Translate(text)).
+		Image()
+
+	got := image.NewRGBA(want.Bounds())
+	draw.Draw(got, got.Bounds(), image.Black, image.Point{}, draw.Src)
+	face := Fnt8x16.Face()
+	d := font.Drawer{
+		Dst:  got,
+		Src:  image.White,
+		Face: face,
+		Dot:  fixed.P(0, face.Metrics().Ascent.Ceil()),
+	}
+	d.DrawString(text)
+
+	b := want.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if !colEq(got.At(x, y), want.At(x, y)) {
+				t.Fatalf("pixel mismatch at %d,%d: got %v, want %v", x, y, got.At(x, y), want.At(x, y))
+			}
+		}
+	}
+}
+
+func TestFNT_Face_wide(t *testing.T) {
+	// 9 pixel wide font, two bytes per row, only the leftmost and the
+	// rightmost pixels of the first row of character 'A' are set.
+	data := make([]byte, CharsetSz*2*2)
+	data['A'*4] = 0x01
+	data['A'*4+1] = 0x01
+	fnt, err := ToFnt(data, 9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fnt.Height != 2 {
+		t.Fatalf("Height = %d, want 2", fnt.Height)
+	}
+	for x, want := range []bool{true, false, false, false, false, false, false, false, true} {
+		if got := fnt.Pixel('A', x, 0); got != want {
+			t.Errorf("Pixel('A', %d, 0) = %v, want %v", x, got, want)
+		}
+	}
+}
+
+func TestLazyFace(t *testing.T) {
+	robotron := func() (*basicfont.Face, error) { return LoadFaceRobotron(), nil }
+	tests := []struct {
+		name string
+		face func() (*basicfont.Face, error)
+		lazy *basicfont.Face
+	}{
+		{"8x8", loadFace8x8, Face8x8},
+		{"8x14", loadFace8x14, Face8x14},
+		{"8x16", loadFace8x16, Face8x16},
+		{"robotron", robotron, FaceRobotron},
+		{"microfont", LoadFace4x4, Face4x4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			face, err := tt.face()
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, want := *tt.lazy, *face
+			got.Mask, want.Mask = nil, nil
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("lazy face metrics = %+v, want %+v", got, want)
+			}
+			mask := face.Mask.(*image.Alpha)
+			if b := tt.lazy.Mask.Bounds(); b != mask.Rect {
+				t.Fatalf("lazy mask bounds = %v, want %v", b, mask.Rect)
+			}
+			for y := mask.Rect.Min.Y; y < mask.Rect.Max.Y; y++ {
+				for x := mask.Rect.Min.X; x < mask.Rect.Max.X; x++ {
+					if got := tt.lazy.Mask.At(x, y); got != mask.At(x, y) {
+						t.Fatalf("lazy mask pixel %d,%d = %v, want %v", x, y, got, mask.At(x, y))
+					}
+				}
+			}
+		})
+	}
+}
@@ -3,10 +3,12 @@ package fontpic
 import (
 	"bytes"
 	_ "embed"
+	"errors"
 	"image"
 	"image/color"
 	"io"
 	"os"
+	"slices"
 )
 
 const (
@@ -21,6 +23,11 @@ type FNT struct {
 	Height  int
 	Charset string
 	Chars   [CharsetSz][]byte
+	// Metrics are the per character metrics of a proportional font.  If
+	// empty, the font is monospaced.  See [FNT.Proportional].
+	Metrics []GlyphMetrics
+	// Kerning is the kerning table of the font, it can be nil.
+	Kerning Kerning
 }
 
 var (
@@ -32,20 +39,6 @@ var (
 	fntKr8x14 []byte
 	//go:embed fnt/08X16.FNT
 	fntKr8x16 []byte
-	//go:embed fnt/microfont.fnt
-	fntMicrofont []byte
-	//go:embed fnt/microfont_bold.fnt
-	fntMicrofontBold []byte
-	//go:embed fnt/microfont_italic.fnt
-	fntMicrofontItalic []byte
-	//go:embed fnt/milifont.fnt
-	fntMilifont []byte
-	//go:embed fnt/font.fnt
-	fntStupidsimplefont []byte
-	//go:embed fnt/font_bold.fnt
-	fntStupidsimplefontBold []byte
-	//go:embed fnt/font_italic.fnt
-	fntStupidsimplefontItalic []byte
 )
 
 var (
@@ -76,7 +69,10 @@ func ToFnt8(b []byte) (*FNT, error) {
 // ToFnt converts byte data to a Font structure.  It detects the font height
 // based on the slice size.
 func ToFnt(b []byte, width int) (*FNT, error) {
-	height := len(b) / CharsetSz
+	height := len(b) / (CharsetSz * charStride(width))
+	if height == 0 {
+		return nil, errors.New("font data is too short")
+	}
 	return &FNT{
 		Width:  width,
 		Height: height,
@@ -124,6 +120,78 @@ func charStride(width int) int {
 	return (width + 7) / 8
 }
 
+// stride returns the number of bytes in a single row of the character.
+func (f *FNT) stride() int {
+	return charStride(f.Width)
+}
+
+// Pixel reports whether the pixel at x, y of the character ch is set.
+func (f *FNT) Pixel(ch byte, x, y int) bool {
+	if x < 0 || x >= f.Width || y < 0 || y >= f.Height {
+		return false
+	}
+	st := f.stride()
+	return bitAt(f.Chars[ch][y*st:(y+1)*st], f.Width, x)
+}
+
+// SetPixel sets or clears the pixel at x, y of the character ch.  It modifies
+// the character bitmap in place, and the bitmaps of the embedded fonts are
+// shared, so it should only be used on the fonts created by the caller.
+func (f *FNT) SetPixel(ch byte, x, y int, on bool) {
+	if x < 0 || x >= f.Width || y < 0 || y >= f.Height {
+		return
+	}
+	st := f.stride()
+	row := f.Chars[ch][y*st : (y+1)*st]
+	bit := f.Width - 1 - x
+	if on {
+		row[len(row)-1-bit/8] |= 1 << uint(bit%8)
+	} else {
+		row[len(row)-1-bit/8] &^= 1 << uint(bit%8)
+	}
+}
+
+// descent returns the number of rows below the baseline.  The KeyRus fonts
+// taller than 8 pixels have two rows of descent, and 8x8 fonts have one.
+func (f *FNT) descent() int {
+	if f.Height > 8 {
+		return 2
+	}
+	return 1
+}
+
+// derive returns a new font of the given size, with the pixels set by the
+// pixel function.  Charset, metrics and kerning are copied from the font.
+func (f *FNT) derive(width, height int, pixel func(ch byte, x, y int) bool) *FNT {
+	nf := &FNT{
+		Width:   width,
+		Height:  height,
+		Charset: f.Charset,
+		Metrics: slices.Clone(f.Metrics),
+		Kerning: f.Kerning,
+	}
+	nf.Chars = toChars(make([]byte, CharsetSz*charStride(width)*height), width, height)
+	for ch := range CharsetSz {
+		for y := range height {
+			for x := range width {
+				if pixel(byte(ch), x, y) {
+					nf.SetPixel(byte(ch), x, y, true)
+				}
+			}
+		}
+	}
+	return nf
+}
+
+// bitAt reports whether the pixel x of the character row is set.  The row is
+// a big-endian integer of one or more bytes, with the rightmost pixel stored
+// in the least significant bit.  For 8 pixel wide fonts this means that the
+// most significant bit is the leftmost pixel.
+func bitAt(row []byte, width, x int) bool {
+	bit := width - 1 - x
+	return row[len(row)-1-bit/8]&(1<<uint(bit%8)) != 0
+}
+
 func toChars(fnt []byte, width int, height int) [CharsetSz][]byte {
 	var chars [CharsetSz][]byte
 	wb := charStride(width)
@@ -142,8 +210,7 @@ func toBytes(chars [CharsetSz][]byte) []byte {
 }
 
 // Sample renders a sample of the font.  The font is rendered in a grid of
-// perLine characters.  The sx and sy parameters are space between characters in
-// pixels
+// perLine characters, perLine is clamped to the range from 1 to CharsetSz.
 func (f *FNT) Sample(perLine int) image.Image {
 	return f.sample(perLine, color.Gray{0xa8}, color.Black, image.Point{1, 1})
 }
@@ -152,16 +219,38 @@ func (f *FNT) SampleColor(perLine int, fg, bg color.Color) image.Image {
 	return f.sample(perLine, fg, bg, image.Point{1, 1})
 }
 
+// clampPerLine returns the number of the characters per line of the sample
+// grid from 1 to CharsetSz.
+func clampPerLine(perLine int) int {
+	return min(max(perLine, 1), CharsetSz)
+}
+
 // sample generates a font sample, with perLine characters, fg foreground and
 // bg background colors,
 func (f *FNT) sample(perLine int, fg, bg color.Color, spacing image.Point) image.Image {
-	perY := CharsetSz / perLine
-	img := image.NewRGBA(image.Rect(0, 0, f.Width*perLine+(spacing.X*perLine), f.Height*perY+(spacing.Y*perY)))
+	cell := image.Pt(f.Width, f.Height)
+	img := image.NewRGBA(image.Rectangle{Max: sampleSize(perLine, cell, spacing)})
 	fill(img, bg)
 	for i := 0; i < len(f.Chars); i++ {
-		x := (i%perLine)*f.Width + spacing.X*(i%perLine)
-		y := (i/perLine)*f.Height + spacing.Y*(i/perLine)
-		RenderCharAt(img, image.Point{x, y}, f.Width, f.Height, f.Chars[i], fg, bg)
+		RenderCharAt(img, sampleAt(perLine, i, cell, spacing), f.Width, f.Height, f.Chars[i], fg, bg)
 	}
 	return img
 }
+
+// sampleSize returns the size of the sample grid of perLine characters per
+// line, with the given character cell size and spacing.  The perLine is
+// clamped, see [clampPerLine].
+func sampleSize(perLine int, cell, spacing image.Point) image.Point {
+	perLine = clampPerLine(perLine)
+	perY := (CharsetSz + perLine - 1) / perLine
+	return image.Pt(cell.X*perLine+(spacing.X*perLine), cell.Y*perY+(spacing.Y*perY))
+}
+
+// sampleAt returns the position of the i-th character in the sample grid.
+func sampleAt(perLine, i int, cell, spacing image.Point) image.Point {
+	perLine = clampPerLine(perLine)
+	return image.Point{
+		X: (i%perLine)*cell.X + spacing.X*(i%perLine),
+		Y: (i/perLine)*cell.Y + spacing.Y*(i/perLine),
+	}
+}
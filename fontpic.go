@@ -9,14 +9,17 @@ import (
 
 // RenderCharAt is a low level function that renders a character, defined in
 // bits, at the given position on the image.  It uses width and height to know
-// how to render the character in bits.
+// how to render the character in bits.  Characters wider than 8 pixels use
+// more than one byte per row.
 func RenderCharAt(img draw.Image, at image.Point, width, height int, bits []byte, hi color.Color, lo color.Color) {
+	stride := charStride(width)
 	for y := 0; y < height; y++ {
+		row := bits[y*stride : (y+1)*stride]
 		for x := 0; x < width; x++ {
-			if bits[y]&(1<<uint(x)) != 0 {
-				img.Set(((width-1)-x)+at.X, y+at.Y, hi)
+			if bitAt(row, width, x) {
+				img.Set(x+at.X, y+at.Y, hi)
 			} else {
-				img.Set(((width-1)-x)+at.X, y+at.Y, lo)
+				img.Set(x+at.X, y+at.Y, lo)
 			}
 		}
 	}
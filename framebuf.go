@@ -0,0 +1,213 @@
+package fontpic
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+)
+
+// framebuf.go contains the encoders of the images and fonts to the memory
+// layouts of the monochrome LCD and OLED controllers.
+
+// Layout is the byte layout of the monochrome display memory.
+type Layout int
+
+const (
+	// LayoutPages is the layout of SSD1306, SH1106 and PCD8544 (Nokia 5110)
+	// controllers: each byte is a vertical column of 8 pixels with the top
+	// pixel in the least significant bit, and the bytes of the page (the band
+	// of 8 rows) go left to right.
+	LayoutPages Layout = iota
+	// LayoutRows is the layout of ST7920, Sharp memory LCDs and most
+	// e-paper controllers: each byte is a horizontal run of 8 pixels with the
+	// left pixel in the most significant bit, and the rows go top to bottom.
+	// Each row is padded to the whole byte.
+	LayoutRows
+)
+
+func (l Layout) String() string {
+	switch l {
+	case LayoutPages:
+		return "pages"
+	case LayoutRows:
+		return "rows"
+	default:
+		return "unknown"
+	}
+}
+
+// FramebufferOptions are the options of the display memory encoding.
+type FramebufferOptions struct {
+	Layout Layout
+	// Invert inverts the pixels, i.e. for the displays, where the set bit
+	// is the dark pixel.
+	Invert bool
+	// Rotate is the clockwise rotation in degrees: 0, 90, 180 or 270.
+	Rotate int
+	// Threshold is the minimum luminance of the lit pixel.  If zero, 0x80 is
+	// used.
+	Threshold uint8
+}
+
+// EncodeFramebuffer encodes the image to the display memory.  The pixels
+// lighter than the threshold are lit, so the text rendered with the light
+// foreground on the dark background shows as is.  The height of the
+// LayoutPages image is rounded up to the whole page, the padding pixels are
+// never set.
+func EncodeFramebuffer(img image.Image, opts FramebufferOptions) ([]byte, error) {
+	if opts.Threshold == 0 {
+		opts.Threshold = 0x80
+	}
+	b := img.Bounds()
+	g := newGrid[bool](b.Dx(), b.Dy())
+	for y := range g.h {
+		for x := range g.w {
+			lum := color.GrayModel.Convert(img.At(b.Min.X+x, b.Min.Y+y)).(color.Gray).Y
+			g.set(x, y, lum >= opts.Threshold)
+		}
+	}
+	return encodeGrid(g, opts)
+}
+
+// Framebuffer encodes the canvas image to the display memory.  See
+// [EncodeFramebuffer].
+func (c *Canvas) Framebuffer(opts FramebufferOptions) ([]byte, error) {
+	return EncodeFramebuffer(c.Image(), opts)
+}
+
+// encodeGrid encodes the pixels to the display memory.
+func encodeGrid(g grid[bool], opts FramebufferOptions) ([]byte, error) {
+	g, err := rotateGrid(g, opts.Rotate)
+	if err != nil {
+		return nil, err
+	}
+	var buf []byte
+	switch opts.Layout {
+	case LayoutPages:
+		buf = make([]byte, (g.h+7)/8*g.w)
+		for y := range g.h {
+			for x := range g.w {
+				if g.at(x, y) != opts.Invert {
+					buf[y/8*g.w+x] |= 1 << (y % 8)
+				}
+			}
+		}
+	case LayoutRows:
+		stride := (g.w + 7) / 8
+		buf = make([]byte, stride*g.h)
+		for y := range g.h {
+			for x := range g.w {
+				if g.at(x, y) != opts.Invert {
+					buf[y*stride+x/8] |= 0x80 >> (x % 8)
+				}
+			}
+		}
+	default:
+		return nil, errors.New("unknown layout")
+	}
+	return buf, nil
+}
+
+// rotateGrid rotates the grid clockwise by the given number of degrees.
+func rotateGrid[T comparable](g grid[T], degrees int) (grid[T], error) {
+	var (
+		w, h = g.w, g.h
+		src  func(x, y int) T
+	)
+	switch degrees {
+	case 0:
+		return g, nil
+	case 90:
+		w, h = g.h, g.w
+		src = func(x, y int) T { return g.at(y, g.h-1-x) }
+	case 180:
+		src = func(x, y int) T { return g.at(g.w-1-x, g.h-1-y) }
+	case 270:
+		w, h = g.h, g.w
+		src = func(x, y int) T { return g.at(g.w-1-y, x) }
+	default:
+		return g, fmt.Errorf("invalid rotation: %d", degrees)
+	}
+	dst := newGrid[T](w, h)
+	for y := range h {
+		for x := range w {
+			dst.set(x, y, src(x, y))
+		}
+	}
+	return dst, nil
+}
+
+// EncodeGlyphs encodes each character of the font to the display memory, as
+// if it was drawn in the character cell.
+func (f *FNT) EncodeGlyphs(opts FramebufferOptions) ([][]byte, error) {
+	ret := make([][]byte, CharsetSz)
+	for ch := range CharsetSz {
+		g := newGrid[bool](f.Width, f.Height)
+		for y := range f.Height {
+			for x := range f.Width {
+				g.set(x, y, f.Pixel(byte(ch), x, y))
+			}
+		}
+		var err error
+		if ret[ch], err = encodeGrid(g, opts); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+// ArrayFormat is the source code format of the arrays.
+type ArrayFormat int
+
+const (
+	// ArrayC is the C array of unsigned chars.
+	ArrayC ArrayFormat = iota
+	// ArrayGo is the Go array of bytes.
+	ArrayGo
+)
+
+// WriteArray writes the font, encoded with the options, as the source code
+// array with the given name, with one row per character.
+func (f *FNT) WriteArray(w io.Writer, name string, format ArrayFormat, opts FramebufferOptions) error {
+	glyphs, err := f.EncodeGlyphs(opts)
+	if err != nil {
+		return err
+	}
+	var (
+		bw      = bufio.NewWriter(w)
+		size    = len(glyphs[0])
+		comment = fmt.Sprintf("%dx%d font, %d characters of %d bytes, %s layout", f.Width, f.Height, len(glyphs), size, opts.Layout)
+	)
+	switch format {
+	case ArrayC:
+		fmt.Fprintf(bw, "/* %s. */\nconst unsigned char %s[%d][%d] = {\n", comment, name, len(glyphs), size)
+	case ArrayGo:
+		fmt.Fprintf(bw, "// %s is the %s.\nvar %s = [%d][%d]byte{\n", name, comment, name, len(glyphs), size)
+	default:
+		return errors.New("unknown array format")
+	}
+	for ch, data := range glyphs {
+		bw.WriteString("\t{")
+		for i, v := range data {
+			if i > 0 {
+				bw.WriteString(", ")
+			}
+			fmt.Fprintf(bw, "0x%02x", v)
+		}
+		if format == ArrayC {
+			fmt.Fprintf(bw, "}, /* 0x%02x */\n", ch)
+		} else {
+			fmt.Fprintf(bw, "}, // 0x%02x\n", ch)
+		}
+	}
+	switch format {
+	case ArrayC:
+		bw.WriteString("};\n")
+	case ArrayGo:
+		bw.WriteString("}\n")
+	}
+	return bw.Flush()
+}
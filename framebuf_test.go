@@ -0,0 +1,155 @@
+package fontpic
+
+import (
+	"bytes"
+	"image"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestEncodeFramebuffer(t *testing.T) {
+	tests := []struct {
+		name    string
+		img     image.Image
+		opts    FramebufferOptions
+		want    []byte
+		wantErr bool
+	}{
+		{
+			"pages",
+			testImage("#..", ".#."),
+			FramebufferOptions{Layout: LayoutPages},
+			[]byte{0x01, 0x02, 0x00},
+			false,
+		},
+		{
+			"pages padded",
+			testImage("#.", "..", "..", "..", "..", "..", "..", "..", ".#"),
+			FramebufferOptions{Layout: LayoutPages},
+			[]byte{0x01, 0x00, 0x00, 0x01},
+			false,
+		},
+		{
+			"pages inverted",
+			testImage("#.", ".."),
+			FramebufferOptions{Layout: LayoutPages, Invert: true},
+			[]byte{0x02, 0x03},
+			false,
+		},
+		{
+			"rows",
+			testImage("#.......#", "........."),
+			FramebufferOptions{Layout: LayoutRows},
+			[]byte{0x80, 0x80, 0x00, 0x00},
+			false,
+		},
+		{
+			"rows inverted",
+			testImage("#."),
+			FramebufferOptions{Layout: LayoutRows, Invert: true},
+			[]byte{0x40},
+			false,
+		},
+		{
+			"rotate 90",
+			testImage("#.", ".."),
+			FramebufferOptions{Layout: LayoutRows, Rotate: 90},
+			[]byte{0x40, 0x00},
+			false,
+		},
+		{
+			"rotate 180",
+			testImage("#.", ".."),
+			FramebufferOptions{Layout: LayoutRows, Rotate: 180},
+			[]byte{0x00, 0x40},
+			false,
+		},
+		{
+			"rotate 270",
+			testImage("#.", ".."),
+			FramebufferOptions{Layout: LayoutRows, Rotate: 270},
+			[]byte{0x00, 0x80},
+			false,
+		},
+		{
+			"rotate 90 non-square",
+			testImage("##."),
+			FramebufferOptions{Layout: LayoutPages, Rotate: 90},
+			[]byte{0x03},
+			false,
+		},
+		{
+			"invalid rotation",
+			testImage("#"),
+			FramebufferOptions{Rotate: 45},
+			nil,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EncodeFramebuffer(tt.img, tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("EncodeFramebuffer() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("EncodeFramebuffer() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFNT_EncodeGlyphs(t *testing.T) {
+	glyphs, err := testFont().EncodeGlyphs(FramebufferOptions{Layout: LayoutPages})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []byte{0x00, 0x0f, 0x00, 0x00}; !slices.Equal(glyphs['I'], want) {
+		t.Errorf("'I' = %#v, want %#v", glyphs['I'], want)
+	}
+	if want := make([]byte, 4); !slices.Equal(glyphs[' '], want) {
+		t.Errorf("' ' = %#v, want %#v", glyphs[' '], want)
+	}
+}
+
+func TestFNT_WriteArray(t *testing.T) {
+	tests := []struct {
+		name   string
+		format ArrayFormat
+		want   []string
+	}{
+		{
+			"c",
+			ArrayC,
+			[]string{
+				"/* 4x4 font, 256 characters of 4 bytes, rows layout. */\n",
+				"const unsigned char font[256][4] = {\n",
+				"\t{0x40, 0x40, 0x40, 0x40}, /* 0x49 */\n",
+				"};\n",
+			},
+		},
+		{
+			"go",
+			ArrayGo,
+			[]string{
+				"// font is the 4x4 font, 256 characters of 4 bytes, rows layout.\n",
+				"var font = [256][4]byte{\n",
+				"\t{0x40, 0x40, 0x40, 0x40}, // 0x49\n",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := testFont().WriteArray(&buf, "font", tt.format, FramebufferOptions{Layout: LayoutRows}); err != nil {
+				t.Fatal(err)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(buf.String(), w) {
+					t.Errorf("output does not contain %q", w)
+				}
+			}
+		})
+	}
+}
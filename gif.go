@@ -0,0 +1,18 @@
+package fontpic
+
+import (
+	"image"
+	"image/draw"
+	"image/gif"
+	"io"
+)
+
+// EncodeGIF writes the image to w in the GIF format.  The palette is made of
+// the exact colours of the image, so the rendered text is not dithered, unless
+// it has more than 256 colours, i.e. with the glow effect.
+func EncodeGIF(w io.Writer, img image.Image) error {
+	pal := imagePalette(img)
+	dst := image.NewPaletted(img.Bounds(), pal)
+	draw.Draw(dst, dst.Bounds(), img, img.Bounds().Min, draw.Src)
+	return gif.Encode(w, dst, &gif.Options{NumColors: len(pal)})
+}
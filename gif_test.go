@@ -0,0 +1,38 @@
+package fontpic
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"testing"
+)
+
+func TestEncodeGIF(t *testing.T) {
+	src := NewCanvas(Fnt8x8).
+		WithForeground(color.RGBA{0xff, 0x55, 0x55, 0xff}).
+		WithBackground(color.RGBA{0x00, 0x00, 0xaa, 0xff}).
+		RenderText([]byte("GIF")).
+		Image()
+	var buf bytes.Buffer
+	if err := EncodeGIF(&buf, src); err != nil {
+		t.Fatal(err)
+	}
+	got, err := gif.Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Bounds() != src.Bounds() {
+		t.Fatalf("bounds = %v, want %v", got.Bounds(), src.Bounds())
+	}
+	if n := len(got.(*image.Paletted).Palette); n != 2 {
+		t.Errorf("palette size = %d, want 2", n)
+	}
+	for y := range src.Bounds().Dy() {
+		for x := range src.Bounds().Dx() {
+			if !colEq(got.At(x, y), src.At(x, y)) {
+				t.Fatalf("pixel %d,%d = %v, want %v", x, y, got.At(x, y), src.At(x, y))
+			}
+		}
+	}
+}
@@ -5,3 +5,5 @@ go 1.23.0
 toolchain go1.24.2
 
 require golang.org/x/image v0.28.0
+
+require golang.org/x/text v0.26.0 // indirect
@@ -4,10 +4,12 @@ import (
 	"embed"
 	"image"
 	"image/color"
-	"path"
 )
 
 var (
+	// imgfontsFS has the images of the embedded fonts, they are decoded on
+	// the first use, see [ImageFont.Ready].
+	//
 	//go:embed imgfonts/*.png
 	imgfontsFS embed.FS
 
@@ -23,7 +25,9 @@ var (
 )
 
 var (
-	// These fonts are GPL2+ font by mibi88
+	// These fonts are GPL2+ font by mibi88.  They are proportional, the widths
+	// of the characters are detected on the first use, clear Proportional and
+	// Metrics of a copy to get the monospaced font.
 	// https://git.planet-casio.com/mibi88/microfont/src/branch/master/microfont.png
 	//
 	//	microfont.png:
@@ -35,32 +39,38 @@ var (
 	//	  grid.padding: 1
 	//	  proportional: false
 	IFMicrofont = ImageFont{
-		Name:        "microfont",
-		Width:       4,
-		GridSize:    image.Pt(4, 4),
-		GridPadding: 1,
-		CharStart:   32,
-		Transparent: color.Transparent,
-		CharEnd:     127,
+		Name:         "microfont",
+		Width:        4,
+		GridSize:     image.Pt(4, 4),
+		GridPadding:  1,
+		CharStart:    32,
+		Transparent:  color.Transparent,
+		CharEnd:      127,
+		Proportional: true,
+		embed:        &embeddedImage{file: "microfont.png"},
 	}
 
 	IFMicrofontBold = ImageFont{
-		Name:        "microfont_bold",
-		Width:       4,
-		GridSize:    image.Pt(4, 4),
-		GridPadding: 1,
-		CharStart:   32,
-		Transparent: color.Transparent,
-		CharEnd:     127,
+		Name:         "microfont_bold",
+		Width:        4,
+		GridSize:     image.Pt(4, 4),
+		GridPadding:  1,
+		CharStart:    32,
+		Transparent:  color.Transparent,
+		CharEnd:      127,
+		Proportional: true,
+		embed:        &embeddedImage{file: "microfont_bold.png"},
 	}
 	IFMicrofontItalic = ImageFont{
-		Name:        "microfont_italic",
-		Width:       4,
-		GridSize:    image.Pt(4, 4),
-		GridPadding: 1,
-		CharStart:   32,
-		Transparent: color.Transparent,
-		CharEnd:     127,
+		Name:         "microfont_italic",
+		Width:        4,
+		GridSize:     image.Pt(4, 4),
+		GridPadding:  1,
+		CharStart:    32,
+		Transparent:  color.Transparent,
+		CharEnd:      127,
+		Proportional: true,
+		embed:        &embeddedImage{file: "microfont_italic.png"},
 	}
 
 	// https://git.planet-casio.com/mibi88/microfont/src/branch/master/milifont.png
@@ -73,53 +83,48 @@ var (
 	//	grid.padding: 1
 	//	proportional: false
 	IFMiliFont = ImageFont{
-		Name:        "milifont",
-		Width:       3,
-		GridSize:    image.Pt(3, 5),
-		GridPadding: 1,
-		CharStart:   32,
-		Transparent: color.Transparent,
-		CharEnd:     127,
+		Name:         "milifont",
+		Width:        3,
+		GridSize:     image.Pt(3, 5),
+		GridPadding:  1,
+		CharStart:    32,
+		Transparent:  color.Transparent,
+		CharEnd:      127,
+		Proportional: true,
+		embed:        &embeddedImage{file: "milifont.png"},
 	}
 
 	IFStupidSimple = ImageFont{
-		Name:        "font",
-		Width:       5,
-		GridSize:    image.Pt(5, 5),
-		GridPadding: 1,
-		CharStart:   32,
-		Transparent: color.Transparent,
-		CharEnd:     127,
+		Name:         "font",
+		Width:        5,
+		GridSize:     image.Pt(5, 5),
+		GridPadding:  1,
+		CharStart:    32,
+		Transparent:  color.Transparent,
+		CharEnd:      127,
+		Proportional: true,
+		embed:        &embeddedImage{file: "font.png"},
 	}
 	IFStupidSimpleBold = ImageFont{
-		Name:        "font_bold",
-		Width:       5,
-		GridSize:    image.Pt(5, 5),
-		GridPadding: 1,
-		CharStart:   32,
-		Transparent: color.Transparent,
-		CharEnd:     127,
+		Name:         "font_bold",
+		Width:        5,
+		GridSize:     image.Pt(5, 5),
+		GridPadding:  1,
+		CharStart:    32,
+		Transparent:  color.Transparent,
+		CharEnd:      127,
+		Proportional: true,
+		embed:        &embeddedImage{file: "font_bold.png"},
 	}
 	IFStupidSimpleItalic = ImageFont{
-		Name:        "font_italic",
-		Width:       5,
-		GridSize:    image.Pt(5, 5),
-		GridPadding: 1,
-		CharStart:   32,
-		Transparent: color.Transparent,
-		CharEnd:     127,
+		Name:         "font_italic",
+		Width:        5,
+		GridSize:     image.Pt(5, 5),
+		GridPadding:  1,
+		CharStart:    32,
+		Transparent:  color.Transparent,
+		CharEnd:      127,
+		Proportional: true,
+		embed:        &embeddedImage{file: "font_italic.png"},
 	}
 )
-
-func init() {
-	for _, fnt := range allImageFonts {
-		f, err := imgfontsFS.Open(path.Join("imgfonts", fnt.Name+".png"))
-		if err != nil {
-			panic(err)
-		}
-		if err := fnt.Load(f); err != nil {
-			panic(err)
-		}
-		f.Close()
-	}
-}
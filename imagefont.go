@@ -3,12 +3,16 @@ package fontpic
 import (
 	"bytes"
 	_ "embed"
+	"errors"
 	"fmt"
 	"image"
 	"image/color"
 	"image/draw"
 	"image/png"
 	"io"
+	"path"
+	"sync"
+	"sync/atomic"
 )
 
 // ImageFont represents a bitmap font loaded from an image file. A great
@@ -24,6 +28,34 @@ type ImageFont struct {
 	Image       image.Image
 	Transparent color.Color
 	Chars       []image.Image
+	// Proportional enables the detection of the character widths on Load.
+	Proportional bool
+	// SpaceWidth is the width of the blank characters of a proportional font.
+	SpaceWidth int
+	// Metrics are the per character metrics, indexed the same way as Chars.
+	// The Advance excludes the grid padding.  If empty, the font is
+	// monospaced.
+	Metrics []GlyphMetrics
+	// Kerning is the kerning table of the font, it can be nil.
+	Kerning Kerning
+
+	// embed is the image of the embedded font, that is loaded on the first
+	// use, or nil.
+	embed *embeddedImage
+}
+
+// embeddedImage is the lazily decoded image of the embedded font.  It is
+// shared by the copies of the font.
+type embeddedImage struct {
+	file  string // file name in imgfontsFS
+	once  sync.Once
+	mu    sync.Mutex // guards the fields of the fonts set by Ready.
+	image *image.Alpha
+	chars []image.Image
+	err   error
+	// loaded is set, once the image and the chars are decoded, they are not
+	// changed after that.
+	loaded atomic.Bool
 }
 
 func nonEmptyAlpha(img image.Image) bool {
@@ -78,16 +110,29 @@ func alphaColor(dst *image.Alpha, img image.Image, bg color.Color) {
 
 }
 
-// Load the font.
+// Load loads the font image from r.  The image of the embedded font, if any,
+// is replaced.
 func (f *ImageFont) Load(r io.Reader) error {
-	img, err := png.Decode(r)
+	img, chars, err := f.decode(r)
 	if err != nil {
 		return err
 	}
+	f.Image, f.Chars, f.embed = img, chars, nil
+	if f.Proportional {
+		f.DetectMetrics()
+	}
+	return nil
+}
+
+// decode decodes the font image and splits it into the character images.
+func (f *ImageFont) decode(r io.Reader) (*image.Alpha, []image.Image, error) {
+	img, err := png.Decode(r)
+	if err != nil {
+		return nil, nil, err
+	}
 	mf := convertRGBA(img)
 	// mf := img.(*image.NRGBA)
-	f.Image = mf
-	f.Chars = make([]image.Image, f.CharEnd-f.CharStart+1)
+	chars := make([]image.Image, f.CharEnd-f.CharStart+1)
 	i := 0
 	for y := 0; y < mf.Bounds().Dy(); y += f.GridSize.Y + f.GridPadding*2 {
 		for x := 0; x < mf.Bounds().Dx(); x += f.GridSize.X + f.GridPadding*2 {
@@ -98,39 +143,135 @@ func (f *ImageFont) Load(r io.Reader) error {
 				y+f.GridSize.Y+f.GridPadding*2,
 			))
 			if c == nil {
-				return fmt.Errorf("error on char %d (%c): nil subimage", i, byte(i)+f.CharStart)
+				return nil, nil, fmt.Errorf("error on char %d (%c): nil subimage", i, byte(i)+f.CharStart)
+			}
+			if i == len(chars) {
+				return mf, chars, nil
 			}
-			f.Chars[i] = c
+			chars[i] = c
 			i++
 		}
 	}
+	if i < len(chars) {
+		return nil, nil, fmt.Errorf("the image has %d characters, want %d", i, len(chars))
+	}
+	return mf, chars, nil
+}
+
+// Ready ensures that the font image is loaded.  The embedded fonts are
+// decoded on the first use, and Ready returns the decoding error, if any.
+// Once the embedded font is decoded, its Image and Chars are set, and the
+// metrics of the proportional font are detected, same as with Load.  For the
+// other fonts, it returns an error, if the font is not loaded with Load.  The
+// methods that do not return errors treat the font that is not ready as
+// blank.
+func (f *ImageFont) Ready() error {
+	e := f.embed
+	if e == nil {
+		if f.Chars == nil {
+			return errors.New("font is not loaded")
+		}
+		return nil
+	}
+	e.once.Do(func() {
+		r, err := imgfontsFS.Open(path.Join("imgfonts", e.file))
+		if err != nil {
+			e.err = err
+			return
+		}
+		defer r.Close()
+		if e.image, e.chars, err = f.decode(r); err != nil {
+			e.err = fmt.Errorf("%s: %w", e.file, err)
+			return
+		}
+		e.loaded.Store(true)
+	})
+	if e.err != nil {
+		return e.err
+	}
+	// the image is shared by the copies of the font, each copy gets the
+	// fields set on its first use.  The copy may be made proportional after
+	// the original is loaded.
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if f.Chars == nil {
+		f.Image, f.Chars = e.image, e.chars
+	}
+	if f.Proportional && f.Metrics == nil {
+		loaded := *f
+		loaded.embed = nil
+		loaded.DetectMetrics()
+		f.Metrics = loaded.Metrics
+	}
 	return nil
 }
 
+// chars returns the character images, or nil, if the font is not ready.  The
+// images of the decoded embedded font are returned without locking.
+func (f *ImageFont) chars() []image.Image {
+	if e := f.embed; e != nil && e.loaded.Load() {
+		return e.chars
+	}
+	if f.Ready() != nil {
+		return nil
+	}
+	return f.Chars
+}
+
+// image returns the font image, or nil, if the font is not ready.
+func (f *ImageFont) image() image.Image {
+	if e := f.embed; e != nil && e.loaded.Load() {
+		return e.image
+	}
+	if f.Ready() != nil {
+		return nil
+	}
+	return f.Image
+}
+
 func (f *ImageFont) charOffset(c byte) int {
 	return int(c - f.CharStart)
 }
 
+// Char returns the image of the character c, including the padding.  If the
+// font is not ready, the image is blank.
 func (f *ImageFont) Char(c byte) image.Image {
-	return f.Chars[f.charOffset(c)]
+	chars := f.chars()
+	if chars == nil {
+		return image.NewAlpha(image.Rect(0, 0, f.GridSize.X+f.GridPadding*2, f.GridSize.Y+f.GridPadding*2))
+	}
+	return chars[f.charOffset(c)]
 }
 
+// DrawChar draws the character c at the given position.  If bg is nil, the
+// background pixels are left intact.
 func (f *ImageFont) DrawChar(dst draw.Image, c byte, at image.Point, fg, bg color.Color) error {
 	if c < f.CharStart || c > f.CharEnd {
 		return fmt.Errorf("character out of range: %c", c)
 	}
+	if err := f.Ready(); err != nil {
+		return err
+	}
 	src := f.Char(c)
 	sp := src.Bounds().Min
+	left, w := f.hmetrics(c)
+	var (
+		adv   = w + f.GridPadding*2
+		cellW = f.GridSize.X + f.GridPadding*2
+	)
 
 	dstfg := dst.ColorModel().Convert(fg)
-	dstbg := dst.ColorModel().Convert(bg)
+	var dstbg color.Color
+	if bg != nil {
+		dstbg = dst.ColorModel().Convert(bg)
+	}
 
 	for dy := 0; dy < f.GridSize.Y+f.GridPadding*2; dy++ {
-		for dx := 0; dx < f.GridSize.X+f.GridPadding*2; dx++ {
-			srcColor := src.At(sp.X+dx, sp.Y+dy)
-			if !colEq(srcColor, f.Transparent) {
+		for dx := 0; dx < adv; dx++ {
+			sx := left + dx
+			if sx < cellW && !colEq(src.At(sp.X+sx, sp.Y+dy), f.Transparent) {
 				dst.Set(at.X+dx, at.Y+dy, dstfg)
-			} else {
+			} else if dstbg != nil {
 				dst.Set(at.X+dx, at.Y+dy, dstbg)
 			}
 		}
@@ -144,18 +285,147 @@ func colEq(a, b color.Color) bool {
 	return ar == br && ag == bg && ab == bb && aa == ba
 }
 
+// WriteString draws the string s at the given position.  The background is
+// painted first, so that the characters brought closer by kerning do not
+// overwrite each other.
 func (f *ImageFont) WriteString(dst draw.Image, s string, at image.Point, fg, bg color.Color) error {
-	for _, c := range s {
-		if err := f.DrawChar(dst, byte(c), at, fg, bg); err != nil {
+	text := toCodes(s)
+	if bg != nil {
+		r := image.Rect(at.X, at.Y, at.X+f.width(text), at.Y+f.GridSize.Y+f.GridPadding*2)
+		draw.Draw(dst, r, image.NewUniform(bg), image.Point{}, draw.Src)
+	}
+	for i, c := range text {
+		if err := f.DrawChar(dst, c, at, fg, nil); err != nil {
 			return err
 		}
-		at.X += f.GridSize.X + f.GridPadding*2
+		at.X += f.advance(c)
+		if i+1 < len(text) {
+			at.X += f.Kern(c, text[i+1])
+		}
 	}
 	return nil
 }
 
+// toCodes converts the string to character codes, truncating each rune to a
+// byte.
+func toCodes(s string) []byte {
+	var codes = make([]byte, 0, len(s))
+	for _, c := range s {
+		codes = append(codes, byte(c))
+	}
+	return codes
+}
+
+// advance returns the advance of the character, including the padding on
+// both sides.
+func (f *ImageFont) advance(c byte) int {
+	_, w := f.hmetrics(c)
+	return w + f.GridPadding*2
+}
+
+// XWidth returns the width of the string in pixels.
 func (f *ImageFont) XWidth(s string) int {
-	return len(s) * (f.GridSize.X + f.GridPadding*2)
+	return f.width(toCodes(s))
+}
+
+// width returns the width of the text in pixels, including the kerning.
+func (f *ImageFont) width(text []byte) int {
+	var w int
+	for i, c := range text {
+		w += f.advance(c)
+		if i+1 < len(text) {
+			w += f.Kern(c, text[i+1])
+		}
+	}
+	return w
+}
+
+// pixel reports whether the pixel x, y of the character c is set.  The
+// coordinates are relative to the character grid, excluding padding.
+func (f *ImageFont) pixel(c byte, x, y int) bool {
+	if c < f.CharStart || c > f.CharEnd {
+		return false
+	}
+	return f.charPixel(f.Char(c), x, y)
+}
+
+// charPixel reports whether the pixel x, y of the character image src, as
+// returned by Char, is set.  It allows to look up the character once per
+// glyph.
+func (f *ImageFont) charPixel(src image.Image, x, y int) bool {
+	sp := src.Bounds().Min
+	return !colEq(src.At(sp.X+f.GridPadding+x, sp.Y+f.GridPadding+y), f.Transparent)
+}
+
+// SetPixel sets or clears the pixel x, y of the character c in the font
+// image.  The coordinates are relative to the character grid, excluding
+// padding.  The font must be ready, see [ImageFont.Ready].
+func (f *ImageFont) SetPixel(c byte, x, y int, on bool) error {
+	if c < f.CharStart || c > f.CharEnd {
+		return fmt.Errorf("character out of range: %c", c)
+	}
+	if x < 0 || x >= f.GridSize.X || y < 0 || y >= f.GridSize.Y {
+		return fmt.Errorf("pixel out of range: %d,%d", x, y)
+	}
+	if err := f.Ready(); err != nil {
+		return err
+	}
+	img, ok := f.image().(draw.Image)
+	if !ok {
+		return errors.New("font image is not editable")
+	}
+	sp := f.Char(c).Bounds().Min
+	col := color.Color(color.Transparent)
+	if on {
+		col = color.Opaque
+	}
+	img.Set(sp.X+f.GridPadding+x, sp.Y+f.GridPadding+y, col)
+	return nil
+}
+
+// WritePNG writes the font image to w in the PNG format, so that it can be
+// loaded with Load.
+func (f *ImageFont) WritePNG(w io.Writer) error {
+	if err := f.Ready(); err != nil {
+		return err
+	}
+	return png.Encode(w, f.image())
+}
+
+// ToFnt converts the loaded image font to FNT, so that it can be used with
+// the Canvas and the font transformations.  The character cell of the
+// resulting font includes the padding on the right and at the bottom, so that
+// the text is spaced the same way as with the font Face.  The metrics and the
+// kerning are preserved.
+func (f *ImageFont) ToFnt() *FNT {
+	var (
+		w = f.GridSize.X + f.GridPadding
+		h = f.GridSize.Y + f.GridPadding
+	)
+	fnt := &FNT{
+		Width:   w,
+		Height:  h,
+		Kerning: f.Kerning,
+		Chars:   toChars(make([]byte, CharsetSz*charStride(w)*h), w, h),
+	}
+	for ch := int(f.CharStart); ch <= int(f.CharEnd); ch++ {
+		src := f.Char(byte(ch))
+		for y := range f.GridSize.Y {
+			for x := range f.GridSize.X {
+				if f.charPixel(src, x, y) {
+					fnt.SetPixel(byte(ch), x, y, true)
+				}
+			}
+		}
+	}
+	if len(f.Metrics) > 0 {
+		fnt.Metrics = make([]GlyphMetrics, CharsetSz)
+		for ch := range CharsetSz {
+			left, adv := f.hmetrics(byte(ch))
+			fnt.Metrics[ch] = GlyphMetrics{Left: left, Advance: adv + f.GridPadding}
+		}
+	}
+	return fnt
 }
 
 // ToBitmap converts the font a byte array. Each byte represents a horizontal
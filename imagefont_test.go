@@ -1,14 +1,18 @@
 package fontpic
 
 import (
+	"bytes"
 	_ "embed"
-	"fmt"
 	"image"
 	"image/color"
 	"image/draw"
 	"image/png"
+	"io"
 	"os"
+	"path/filepath"
 	"testing"
+
+	"golang.org/x/image/font/basicfont"
 )
 
 func TestMicrofont(t *testing.T) {
@@ -23,7 +27,7 @@ func TestMicrofont(t *testing.T) {
 			fnt.DrawChar(dst, 'B', image.Pt(6, 6), color.Black, color.White)
 			fnt.WriteString(dst, "Hello, World!", image.Pt(0, 12), color.Black, color.White)
 
-			f, err := os.Create(fmt.Sprintf("%s.png", fnt.Name))
+			f, err := os.Create(filepath.Join(t.TempDir(), fnt.Name+".png"))
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -36,8 +40,9 @@ func TestMicrofont(t *testing.T) {
 }
 
 func TestFont_ToBitmap(t *testing.T) {
+	dir := t.TempDir()
 	for _, fnt := range allImageFonts {
-		f, err := os.Create(fmt.Sprintf("%s.fnt", fnt.Name))
+		f, err := os.Create(filepath.Join(dir, fnt.Name+".fnt"))
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -47,3 +52,192 @@ func TestFont_ToBitmap(t *testing.T) {
 		}
 	}
 }
+
+func TestImageFont_Face(t *testing.T) {
+	tests := []struct {
+		name    string
+		face    func() (*basicfont.Face, error)
+		lazy    *basicfont.Face
+		fnt     *ImageFont
+		fntFile string
+	}{
+		{"microfont", LoadFace4x4, Face4x4, &IFMicrofont, "fnt/microfont.fnt"},
+		{"microfont bold", LoadFace4x4Bold, Face4x4Bold, &IFMicrofontBold, "fnt/microfont_bold.fnt"},
+		{"milifont", LoadFace4x5, Face4x5, &IFMiliFont, "fnt/milifont.fnt"},
+		{"stupid simple", LoadFace6x5, Face6x5, &IFStupidSimple, "fnt/font.fnt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := os.ReadFile(tt.fntFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			face, err := tt.face()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := tt.fnt.GridSize.X + tt.fnt.GridPadding; face.Advance != want {
+				t.Errorf("Advance = %d, want %d", face.Advance, want)
+			}
+			if tt.lazy.Advance != face.Advance || tt.lazy.Height != face.Height || tt.lazy.Mask.Bounds() != face.Mask.Bounds() {
+				t.Errorf("lazy face metrics differ from the loaded face")
+			}
+			height := face.Ascent + face.Descent
+			mask := face.Mask.(*image.Alpha)
+			for ch := int(tt.fnt.CharStart); ch <= int(tt.fnt.CharEnd); ch++ {
+				for y := range height {
+					row := data[ch*height+y : ch*height+y+1]
+					for x := range face.Width {
+						want := bitAt(row, face.Width, x)
+						got := mask.AlphaAt(x, (ch-int(tt.fnt.CharStart))*height+y).A != 0
+						if got != want {
+							t.Fatalf("char %q pixel %d,%d = %v, want %v", rune(ch), x, y, got, want)
+						}
+						if lazy := tt.lazy.Mask.At(x, (ch-int(tt.fnt.CharStart))*height+y); lazy != mask.At(x, (ch-int(tt.fnt.CharStart))*height+y) {
+							t.Fatalf("char %q pixel %d,%d of the lazy face = %v", rune(ch), x, y, lazy)
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestImageFont_SetPixel(t *testing.T) {
+	load := func(r io.Reader) *ImageFont {
+		f := IFMicrofont
+		f.Image, f.Chars = nil, nil
+		if err := f.Load(r); err != nil {
+			t.Fatal(err)
+		}
+		return &f
+	}
+	src, err := imgfontsFS.Open("imgfonts/microfont.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+	fnt := load(src)
+
+	tests := []struct {
+		name    string
+		c       byte
+		x, y    int
+		on      bool
+		wantErr bool
+	}{
+		{"set", ' ', 1, 2, true, false},
+		{"clear", 'A', 0, 3, false, false},
+		{"character out of range", 0x10, 0, 0, true, true},
+		{"pixel out of range", 'A', 4, 0, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := fnt.SetPixel(tt.c, tt.x, tt.y, tt.on)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SetPixel() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && fnt.pixel(tt.c, tt.x, tt.y) != tt.on {
+				t.Errorf("pixel = %v, want %v", !tt.on, tt.on)
+			}
+		})
+	}
+
+	// the changes survive the round trip.
+	var buf bytes.Buffer
+	if err := fnt.WritePNG(&buf); err != nil {
+		t.Fatal(err)
+	}
+	got := load(&buf)
+	if !got.pixel(' ', 1, 2) || got.pixel('A', 0, 3) {
+		t.Error("changes are lost after WritePNG")
+	}
+	if got.pixel('A', 1, 0) != fnt.pixel('A', 1, 0) {
+		t.Error("unchanged pixel differs after WritePNG")
+	}
+}
+
+func TestImageFont_Ready(t *testing.T) {
+	lazy := IFMiliFont
+	lazy.embed = &embeddedImage{file: "milifont.png"}
+	if lazy.embed.chars != nil {
+		t.Fatal("font is loaded before the first use")
+	}
+	if !lazy.pixel('A', 1, 0) {
+		t.Error("pixel of the lazily loaded font is not set")
+	}
+	if lazy.embed.chars == nil {
+		t.Error("font image is not cached in the embedded image")
+	}
+	if lazy.Image == nil || len(lazy.Chars) != int(lazy.CharEnd-lazy.CharStart)+1 {
+		t.Error("Image and Chars are not set after the first use")
+	}
+
+	// the copy of the embedded font detects the metrics, same as Load.
+	prop := IFMicrofont
+	prop.Proportional = true
+	if err := prop.Ready(); err != nil {
+		t.Fatal(err)
+	}
+	if len(prop.Metrics) == 0 {
+		t.Error("metrics of the proportional font are not detected")
+	}
+	if l, w := prop.hmetrics('i'); w >= prop.GridSize.X {
+		t.Errorf("hmetrics('i') = %d, %d, want narrower than the grid", l, w)
+	}
+
+	missing := IFMiliFont
+	missing.embed = &embeddedImage{file: "missing.png"}
+	tests := []struct {
+		name    string
+		fnt     *ImageFont
+		wantErr bool
+	}{
+		{"embedded", &IFMicrofontItalic, false},
+		{"lazy", &lazy, false},
+		{"missing", &missing, true},
+		{"not loaded", &ImageFont{GridSize: image.Pt(3, 5), GridPadding: 1, CharStart: 32, CharEnd: 127, Transparent: color.Transparent}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.fnt.Ready(); (err != nil) != tt.wantErr {
+				t.Fatalf("Ready() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			dst := image.NewRGBA(image.Rect(0, 0, 8, 8))
+			if err := tt.fnt.DrawChar(dst, 'A', image.Point{}, color.White, nil); (err != nil) != tt.wantErr {
+				t.Errorf("DrawChar() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if _, err := imageFace(tt.fnt)(); (err != nil) != tt.wantErr {
+				t.Errorf("imageFace() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr {
+				return
+			}
+			// the methods without errors treat the font as blank.
+			if tt.fnt.pixel('A', 1, 0) {
+				t.Error("pixel of the font that is not ready is set")
+			}
+			if got := tt.fnt.Char('A').Bounds().Size(); got != image.Pt(5, 7) {
+				t.Errorf("Char() size = %v, want 5x7", got)
+			}
+		})
+	}
+}
+
+func TestImageFont_ToFnt(t *testing.T) {
+	fnt := IFMicrofont.ToFnt()
+	if fnt.Width != 5 || fnt.Height != 5 {
+		t.Errorf("size = %dx%d, want 5x5", fnt.Width, fnt.Height)
+	}
+	for ch := int(IFMicrofont.CharStart); ch <= int(IFMicrofont.CharEnd); ch++ {
+		for y := range fnt.Height {
+			for x := range fnt.Width {
+				if got, want := fnt.Pixel(byte(ch), x, y), IFMicrofont.pixel(byte(ch), x, y); got != want && x < 4 && y < 4 {
+					t.Fatalf("Pixel(%q, %d, %d) = %v, want %v", rune(ch), x, y, got, want)
+				} else if got && (x >= 4 || y >= 4) {
+					t.Fatalf("Pixel(%q, %d, %d) is set in the padding", rune(ch), x, y)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package fontpic
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rusq/fontpic/charset"
+)
+
+// kerning.go contains support for the kerning pairs.
+
+// KernPair is a pair of adjacent character codes.
+type KernPair [2]byte
+
+// Kerning is the kerning table.  It maps the pairs of adjacent characters to
+// the adjustment of the advance of the first character, in pixels.  Negative
+// values move the characters closer together.
+type Kerning map[KernPair]int
+
+// Kern returns the kerning adjustment for the pair of characters.
+func (k Kerning) Kern(a, b byte) int {
+	return k[KernPair{a, b}]
+}
+
+// ParseKerning parses the kerning table in the text format.  Each line
+// contains a pair of characters, followed by spaces or tabs and the
+// adjustment in pixels, i.e.:
+//
+//	AV -1
+//	Te -1
+//	r. -1
+//
+// Empty lines and lines starting with # are ignored.  The pair, that has a
+// space or starts with #, is written as the Go quoted string, i.e. "A " or
+// "#A", the escapes, such as "\x23A", are allowed.  The characters are
+// translated to the character codes using the charset cs.  If cs is empty,
+// only ASCII characters are allowed.
+func ParseKerning(r io.Reader, cs charset.Charset) (Kerning, error) {
+	var (
+		k    = make(Kerning)
+		scan = bufio.NewScanner(r)
+		n    = 0
+	)
+	for scan.Scan() {
+		n++
+		line := strings.TrimSpace(scan.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		pair := fields[0]
+		if strings.HasPrefix(line, `"`) {
+			quoted, err := strconv.QuotedPrefix(line)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid quoted pair: %w", n, err)
+			}
+			pair, _ = strconv.Unquote(quoted)
+			fields = append([]string{quoted}, strings.Fields(line[len(quoted):])...)
+		}
+		if len(fields) != 2 || utf8.RuneCountInString(pair) != 2 {
+			return nil, fmt.Errorf("line %d: expected a pair of characters and an adjustment", n)
+		}
+		v, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		var kp KernPair
+		for i, r := range []rune(pair) {
+			b, ok := cs.Lookup(r)
+			if !ok {
+				return nil, fmt.Errorf("line %d: character %q is not in the charset", n, r)
+			}
+			kp[i] = b
+		}
+		k[kp] = v
+	}
+	if err := scan.Err(); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// WithKerning returns a copy of the font with the kerning table k.  The
+// character bitmaps are shared with the original font.
+func (f *FNT) WithKerning(k Kerning) *FNT {
+	nf := *f
+	nf.Kerning = k
+	return &nf
+}
+
+// Kern returns the kerning adjustment between the characters a and b.
+func (f *FNT) Kern(a, b byte) int {
+	return f.Kerning.Kern(a, b)
+}
+
+// Kern returns the kerning adjustment between the characters a and b.
+func (f *ImageFont) Kern(a, b byte) int {
+	return f.Kerning.Kern(a, b)
+}
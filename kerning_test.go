@@ -0,0 +1,120 @@
+package fontpic
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/image/math/fixed"
+
+	"github.com/rusq/fontpic/charset"
+)
+
+func TestParseKerning(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		cs      charset.Charset
+		want    Kerning
+		wantErr bool
+	}{
+		{
+			name:  "ascii",
+			input: "# comment\nAV -1\n\nTe  -2\nr. -1\n",
+			want:  Kerning{{'A', 'V'}: -1, {'T', 'e'}: -2, {'r', '.'}: -1},
+		},
+		{
+			name:  "cyrillic",
+			input: "Гд -1",
+			cs:    charset.CP866,
+			want:  Kerning{{0x83, 0xA4}: -1},
+		},
+		{
+			name:    "not in charset",
+			input:   "Гд -1",
+			wantErr: true,
+		},
+		{
+			name:  "tabs",
+			input: "AV\t-1\nTe \t -2\n",
+			want:  Kerning{{'A', 'V'}: -1, {'T', 'e'}: -2},
+		},
+		{
+			name:    "extra field",
+			input:   "AV -1 2",
+			wantErr: true,
+		},
+		{
+			name:    "no adjustment",
+			input:   "AV",
+			wantErr: true,
+		},
+		{
+			name:    "not a pair",
+			input:   "AVA -1",
+			wantErr: true,
+		},
+		{
+			name:  "quoted",
+			input: "\"A \" -1\n\"#A\"\t-2\n\"\\x23B\" 1\n\"Гд\" -3\n",
+			cs:    charset.CP866,
+			want:  Kerning{{'A', ' '}: -1, {'#', 'A'}: -2, {'#', 'B'}: 1, {0x83, 0xA4}: -3},
+		},
+		{
+			name:    "unterminated quote",
+			input:   "\"A -1",
+			wantErr: true,
+		},
+		{
+			name:    "quoted not a pair",
+			input:   "\"A\" -1",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseKerning(strings.NewReader(tt.input), tt.cs)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseKerning() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseKerning() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFNT_Kerning(t *testing.T) {
+	fnt := Fnt8x16.WithKerning(Kerning{{'A', 'V'}: -2, {0x83, 0xA4}: -1})
+	if got := fnt.TextWidth([]byte("AVA")); got != 8*3-2 {
+		t.Errorf("TextWidth() = %d, want %d", got, 8*3-2)
+	}
+	if c := NewCanvas(fnt).RenderText([]byte("AV")); c.Width != 14 {
+		t.Errorf("Canvas.Width = %d, want 14", c.Width)
+	}
+	face := fnt.Face()
+	tests := []struct {
+		r0, r1 rune
+		want   fixed.Int26_6
+	}{
+		{'A', 'V', fixed.I(-2)},
+		{'V', 'A', 0},
+		{'Г', 'д', fixed.I(-1)},
+	}
+	for _, tt := range tests {
+		if got := face.Kern(tt.r0, tt.r1); got != tt.want {
+			t.Errorf("Kern(%q, %q) = %v, want %v", tt.r0, tt.r1, got, tt.want)
+		}
+	}
+}
+
+func TestImageFont_Kerning(t *testing.T) {
+	fnt := IFMicrofont
+	fnt.Kerning = Kerning{{'T', 'e'}: -1}
+	if got, want := fnt.XWidth("Te"), IFMicrofont.XWidth("Te")-1; got != want {
+		t.Errorf("XWidth() = %d, want %d", got, want)
+	}
+	if got := fnt.Face().Kern('T', 'e'); got != fixed.I(-1) {
+		t.Errorf("Face().Kern() = %v, want %v", got, fixed.I(-1))
+	}
+}
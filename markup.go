@@ -0,0 +1,116 @@
+package fontpic
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// markup.go contains the parser of the simple markup of the rich text, that
+// is rendered with [Canvas.RenderSpans].
+
+// markupStyles are the style tags of the markup.
+var markupStyles = map[string]Style{
+	"b": StyleBold,
+	"i": StyleItalic,
+	"u": StyleUnderline,
+	"s": StyleStrikeout,
+	"o": StyleOverline,
+}
+
+// ParseMarkup parses the text with the markup tags to the spans.  The tags
+// are:
+//
+//   - [b], [i], [u], [s] and [o] - bold, italic, underline, strikeout and
+//     overline style, see [Style];
+//   - [color=c] and [bg=c] - the foreground and the background colour, in
+//     any form accepted by [ParseColor], i.e. [color=#f00] or [bg=blue];
+//   - [font=name] - the registered font, see [LookupFont].
+//
+// The tag is closed with the slash, i.e. [/b] or [/color], which also closes
+// the tags opened after it.  The tags, that are left open, end with the text.
+// The tag names are case insensitive, and "[[" is the literal "[".  The text
+// is in the character set of the fonts, same as with RenderText.
+func ParseMarkup(text []byte) ([]Span, error) {
+	type open struct {
+		tag  string
+		prev Span // span attributes before the tag.
+	}
+	var (
+		spans []Span
+		cur   Span
+		stack []open
+	)
+	flush := func() {
+		if len(cur.Text) > 0 {
+			spans = append(spans, cur)
+			cur.Text = nil
+		}
+	}
+	for i := 0; i < len(text); i++ {
+		if text[i] != '[' {
+			cur.Text = append(cur.Text, text[i])
+			continue
+		}
+		if i+1 < len(text) && text[i+1] == '[' {
+			cur.Text = append(cur.Text, '[')
+			i++
+			continue
+		}
+		end := bytes.IndexByte(text[i:], ']')
+		if end < 0 {
+			return nil, fmt.Errorf("markup offset %d: unterminated tag", i)
+		}
+		tag := string(text[i+1 : i+end])
+		name, value, hasValue := strings.Cut(tag, "=")
+		name = strings.ToLower(strings.TrimSpace(name))
+		value = strings.TrimSpace(value)
+
+		if closing, ok := strings.CutPrefix(name, "/"); ok {
+			idx := -1
+			for j := len(stack) - 1; j >= 0; j-- {
+				if stack[j].tag == closing {
+					idx = j
+					break
+				}
+			}
+			if idx < 0 || hasValue {
+				return nil, fmt.Errorf("markup offset %d: unexpected tag [%s]", i, tag)
+			}
+			flush()
+			cur = stack[idx].prev
+			stack = stack[:idx]
+			i += end
+			continue
+		}
+
+		next := cur
+		next.Text = nil
+		var err error
+		switch style, isStyle := markupStyles[name]; {
+		case isStyle && hasValue:
+			err = fmt.Errorf("tag [%s] has no value", name)
+		case isStyle:
+			next.Style |= style
+		case name != "color" && name != "bg" && name != "font":
+			err = fmt.Errorf("unknown tag [%s]", tag)
+		case value == "":
+			err = fmt.Errorf("tag [%s] requires a value", name)
+		case name == "color":
+			next.Foreground, err = ParseColor(value)
+		case name == "bg":
+			next.Background, err = ParseColor(value)
+		case name == "font":
+			next.Font, err = LookupFont(value)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("markup offset %d: %w", i, err)
+		}
+		flush()
+		stack = append(stack, open{tag: name, prev: cur})
+		cur = next
+		i += end
+	}
+	flush()
+	return spans, nil
+}
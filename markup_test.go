@@ -0,0 +1,70 @@
+package fontpic
+
+import (
+	"errors"
+	"image/color"
+	"reflect"
+	"testing"
+)
+
+func TestParseMarkup(t *testing.T) {
+	red := color.NRGBA{0xff, 0, 0, 0xff}
+	micro := Must(LookupFont("microfont"))
+	tests := []struct {
+		name    string
+		text    string
+		want    []Span
+		wantErr bool
+	}{
+		{"plain", "hello", []Span{{Text: []byte("hello")}}, false},
+		{"empty", "", nil, false},
+		{
+			"style",
+			"a[b]b[/b]c",
+			[]Span{{Text: []byte("a")}, {Text: []byte("b"), Style: StyleBold}, {Text: []byte("c")}},
+			false,
+		},
+		{
+			"nested",
+			"[B][i]x[/i]y[/b]",
+			[]Span{{Text: []byte("x"), Style: StyleBold | StyleItalic}, {Text: []byte("y"), Style: StyleBold}},
+			false,
+		},
+		{
+			"closing outer closes inner",
+			"[u][s]x[/u]y",
+			[]Span{{Text: []byte("x"), Style: StyleUnderline | StyleStrikeout}, {Text: []byte("y")}},
+			false,
+		},
+		{
+			"colours",
+			"[color=#f00][bg=transparent]x[/bg]y",
+			[]Span{{Text: []byte("x"), Foreground: red, Background: color.Transparent}, {Text: []byte("y"), Foreground: red}},
+			false,
+		},
+		{"font", "[font=microfont]x", []Span{{Text: []byte("x"), Font: micro}}, false},
+		{"open until the end", "[o]x", []Span{{Text: []byte("x"), Style: StyleOverline}}, false},
+		{"escape", "[[b]", []Span{{Text: []byte("[b]")}}, false},
+		{"unknown tag", "[blink]x", nil, true},
+		{"unterminated", "x[b", nil, true},
+		{"unexpected closing", "x[/b]", nil, true},
+		{"style with value", "[b=1]x", nil, true},
+		{"missing value", "[color]x", nil, true},
+		{"invalid colour", "[color=nope]x", nil, true},
+		{"unknown font", "[font=nope]x", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMarkup([]byte(tt.text))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseMarkup() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseMarkup() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+	if _, err := ParseMarkup([]byte("[font=nope]")); !errors.Is(err, ErrFontNotFound) {
+		t.Errorf("error = %v, want ErrFontNotFound", err)
+	}
+}
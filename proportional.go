@@ -0,0 +1,217 @@
+package fontpic
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// proportional.go contains support for the variable width fonts.
+
+// GlyphMetrics are the horizontal metrics of a single character of a
+// proportional font.
+type GlyphMetrics struct {
+	// Left is the left bearing, the number of blank columns of the character
+	// bitmap, that are skipped when the character is rendered.
+	Left int
+	// Advance is the number of columns rendered, and the distance the pen
+	// moves after the character is rendered.
+	Advance int
+}
+
+// inkBounds returns the first inked column and the inked width of the glyph.
+// For a blank glyph it returns 0, 0.
+func inkBounds(width, height int, pixel func(x, y int) bool) (left, w int) {
+	left, right := width, -1
+	for y := range height {
+		for x := range width {
+			if pixel(x, y) {
+				left = min(left, x)
+				right = max(right, x)
+			}
+		}
+	}
+	if right < 0 {
+		return 0, 0
+	}
+	return left, right - left + 1
+}
+
+// Proportional returns a copy of the font with the character metrics
+// detected from the inked width of each character.  The gap is the number of
+// blank columns added after each character.  Blank characters, such as space,
+// are space pixels wide; if space is less than 1, half of the font width is
+// used.  The character bitmaps are shared with the original font.
+func (f *FNT) Proportional(space, gap int) *FNT {
+	if space < 1 {
+		space = max(1, f.Width/2)
+	}
+	nf := *f
+	nf.Metrics = make([]GlyphMetrics, CharsetSz)
+	for ch := range CharsetSz {
+		left, w := inkBounds(f.Width, f.Height, func(x, y int) bool {
+			return f.Pixel(byte(ch), x, y)
+		})
+		if w == 0 {
+			nf.Metrics[ch] = GlyphMetrics{Left: 0, Advance: space}
+			continue
+		}
+		nf.Metrics[ch] = GlyphMetrics{Left: left, Advance: w + gap}
+	}
+	return &nf
+}
+
+// hmetrics returns the left bearing and the advance of the character.
+func (f *FNT) hmetrics(ch byte) (left, advance int) {
+	if len(f.Metrics) != CharsetSz {
+		return 0, f.Width
+	}
+	return f.Metrics[ch].Left, f.Metrics[ch].Advance
+}
+
+// Advance returns the advance width of the character in pixels.  For
+// monospaced fonts it is the font width.
+func (f *FNT) Advance(ch byte) int {
+	_, adv := f.hmetrics(ch)
+	return adv
+}
+
+// TextWidth returns the width of the text in pixels, including the kerning.
+func (f *FNT) TextWidth(text []byte) int {
+	var w int
+	for i, ch := range text {
+		w += f.Advance(ch)
+		if i+1 < len(text) {
+			w += f.Kern(ch, text[i+1])
+		}
+	}
+	return w
+}
+
+// drawChar renders the character ch at the given position, honouring the
+// metrics of proportional fonts, and returns the advance.  If bg is nil, the
+// background pixels are left intact.
+func (f *FNT) drawChar(img draw.Image, at image.Point, ch byte, fg, bg color.Color) int {
+	left, adv := f.hmetrics(ch)
+	for y := range f.Height {
+		for x := range adv {
+			if f.Pixel(ch, left+x, y) {
+				img.Set(at.X+x, at.Y+y, fg)
+			} else if bg != nil {
+				img.Set(at.X+x, at.Y+y, bg)
+			}
+		}
+	}
+	return adv
+}
+
+// DetectMetrics detects the inked width of each character of the loaded font
+// and sets the Metrics.  Blank characters are SpaceWidth pixels wide, or half
+// of the grid width, if SpaceWidth is not set.
+func (f *ImageFont) DetectMetrics() {
+	space := f.SpaceWidth
+	if space < 1 {
+		space = max(1, f.GridSize.X/2)
+	}
+	f.Metrics = make([]GlyphMetrics, int(f.CharEnd)-int(f.CharStart)+1)
+	for i := range f.Metrics {
+		src := f.Char(f.CharStart + byte(i))
+		left, w := inkBounds(f.GridSize.X, f.GridSize.Y, func(x, y int) bool {
+			return f.charPixel(src, x, y)
+		})
+		if w == 0 {
+			w = space
+		}
+		f.Metrics[i] = GlyphMetrics{Left: left, Advance: w}
+	}
+}
+
+// hmetrics returns the left bearing and the width of the character, excluding
+// the grid padding.
+func (f *ImageFont) hmetrics(c byte) (left, width int) {
+	if f.Proportional {
+		f.Ready() // the metrics of the embedded font are detected on load.
+	}
+	if len(f.Metrics) == 0 || c < f.CharStart || c > f.CharEnd {
+		return 0, f.GridSize.X
+	}
+	m := f.Metrics[f.charOffset(c)]
+	return m.Left, m.Advance
+}
+
+// propFace is a basicfont.Face with variable glyph advances and kerning.
+type propFace struct {
+	*basicfont.Face
+	// metrics are indexed by the glyph index in the Mask.
+	metrics []GlyphMetrics
+	// kern is the kerning table, first is the character code of the first
+	// glyph in the Mask.
+	kern  Kerning
+	first byte
+}
+
+var _ font.Face = (*propFace)(nil)
+
+// index returns the index of the glyph in the Mask, that is used to render
+// rune r, or -1 if there's no such glyph.
+func (f *propFace) index(r rune) int {
+	_, mask, maskp, _, _ := f.Face.Glyph(fixed.Point26_6{}, r)
+	if mask == nil {
+		return -1
+	}
+	return maskp.Y / (f.Ascent + f.Descent)
+}
+
+func (f *propFace) Kern(r0, r1 rune) fixed.Int26_6 {
+	if len(f.kern) == 0 {
+		return 0
+	}
+	i0, i1 := f.index(r0), f.index(r1)
+	if i0 < 0 || i1 < 0 {
+		return 0
+	}
+	return fixed.I(f.kern.Kern(byte(i0)+f.first, byte(i1)+f.first))
+}
+
+// glyph returns the metrics of the glyph that is used to render rune r.  The
+// found is false if neither r nor the replacement character are in the face.
+func (f *propFace) glyph(r rune) (m GlyphMetrics, found, ok bool) {
+	_, mask, maskp, _, ok := f.Face.Glyph(fixed.Point26_6{}, r)
+	if mask == nil {
+		return GlyphMetrics{}, false, false
+	}
+	idx := maskp.Y / (f.Ascent + f.Descent)
+	if idx >= len(f.metrics) {
+		return GlyphMetrics{Advance: f.Advance}, true, ok
+	}
+	return f.metrics[idx], true, ok
+}
+
+func (f *propFace) Glyph(dot fixed.Point26_6, r rune) (dr image.Rectangle, mask image.Image, maskp image.Point, advance fixed.Int26_6, ok bool) {
+	dr, mask, maskp, _, ok = f.Face.Glyph(dot, r)
+	if mask == nil {
+		return
+	}
+	m, _, _ := f.glyph(r)
+	return dr.Sub(image.Pt(m.Left, 0)), mask, maskp, fixed.I(m.Advance), ok
+}
+
+func (f *propFace) GlyphBounds(r rune) (bounds fixed.Rectangle26_6, advance fixed.Int26_6, ok bool) {
+	m, found, ok := f.glyph(r)
+	if !found {
+		return fixed.Rectangle26_6{}, 0, false
+	}
+	return fixed.R(-m.Left, -f.Ascent, f.Width-m.Left, f.Descent), fixed.I(m.Advance), ok
+}
+
+func (f *propFace) GlyphAdvance(r rune) (advance fixed.Int26_6, ok bool) {
+	m, found, ok := f.glyph(r)
+	if !found {
+		return 0, false
+	}
+	return fixed.I(m.Advance), ok
+}
@@ -0,0 +1,162 @@
+package fontpic
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"golang.org/x/image/math/fixed"
+)
+
+func TestFNT_Proportional(t *testing.T) {
+	fnt := Fnt8x16.Proportional(3, 1)
+	tests := []struct {
+		name string
+		ch   byte
+		want int
+	}{
+		{"space", ' ', 3},
+		{"i is narrow", 'i', 5},
+		{"W is wide", 'W', 8},
+		{"blank glyph 0", 0, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fnt.Advance(tt.ch); got != tt.want {
+				t.Errorf("Advance(%q) = %d, want %d", tt.ch, got, tt.want)
+			}
+		})
+	}
+	if adv, _ := fnt.Face().GlyphAdvance('i'); adv != fixed.I(5) {
+		t.Errorf("Face().GlyphAdvance('i') = %v, want %v", adv, fixed.I(5))
+	}
+	if Fnt8x16.Metrics != nil {
+		t.Error("Proportional() modified the original font")
+	}
+}
+
+func TestFNT_TextWidth(t *testing.T) {
+	tests := []struct {
+		name string
+		fnt  *FNT
+		text string
+		want int
+	}{
+		{"monospace", Fnt8x8, "Hi!", 24},
+		{"proportional", Fnt8x16.Proportional(3, 1), "i i", 5 + 3 + 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.fnt.TextWidth([]byte(tt.text)); got != tt.want {
+				t.Errorf("TextWidth() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanvas_proportional(t *testing.T) {
+	fnt := Fnt8x16.Proportional(3, 1)
+	c := NewCanvas(fnt).WithSpacing(1, 0).RenderText([]byte("ii"))
+	if want := (5 + 1) * 2; c.Width != want {
+		t.Errorf("Width = %d, want %d", c.Width, want)
+	}
+	// the second 'i' starts right after the first one and the spacing, so
+	// the image should have two identical halves.
+	img := c.Image()
+	for y := range fnt.Height {
+		for x := range 6 {
+			if a, b := img.At(x, y), img.At(x+6, y); !colEq(a, b) {
+				t.Fatalf("pixel %d,%d = %v, want %v", x+6, y, b, a)
+			}
+		}
+	}
+}
+
+func TestImageFont_DetectMetrics(t *testing.T) {
+	fnt := IFMicrofont
+	fnt.SpaceWidth, fnt.Metrics = 2, nil
+	fnt.DetectMetrics()
+	metrics := []struct {
+		ch          byte
+		wantLeft    int
+		wantAdvance int
+	}{
+		{' ', 0, 2},
+		{'H', 0, 3},
+		{'i', 0, 1},
+		{'!', 1, 1},
+	}
+	for _, tt := range metrics {
+		if left, adv := fnt.hmetrics(tt.ch); left != tt.wantLeft || adv != tt.wantAdvance {
+			t.Errorf("hmetrics(%q) = %d, %d, want %d, %d", tt.ch, left, adv, tt.wantLeft, tt.wantAdvance)
+		}
+	}
+	pad := fnt.GridPadding
+	if got, want := fnt.XWidth("Hi!"), 3+1+1+3*2*pad; got != want {
+		t.Errorf("XWidth(Hi!) = %d, want %d", got, want)
+	}
+	mono := IFMicrofont
+	mono.Proportional, mono.Metrics = false, nil
+	if got, want := mono.XWidth("Hi!"), 3*(fnt.GridSize.X+2*pad); got != want {
+		t.Errorf("monospaced XWidth(Hi!) = %d, want %d", got, want)
+	}
+
+	// every glyph is drawn from its left bearing, padded on both sides.
+	dst := image.NewRGBA(image.Rect(0, 0, fnt.XWidth("Hi!"), fnt.GridSize.Y+2*pad))
+	if err := fnt.WriteString(dst, "Hi!", image.Point{}, color.White, color.Black); err != nil {
+		t.Fatal(err)
+	}
+	var x, inked int
+	for _, ch := range []byte("Hi!") {
+		left, w := fnt.hmetrics(ch)
+		for dy := range dst.Rect.Dy() {
+			for dx := range w + 2*pad {
+				want := fnt.pixel(ch, left+dx-pad, dy-pad)
+				if got := dst.RGBAAt(x+dx, dy) == (color.RGBA{255, 255, 255, 255}); got != want {
+					t.Fatalf("char %q pixel %d,%d = %v, want %v", ch, dx, dy, got, want)
+				}
+				if want {
+					inked++
+				}
+			}
+		}
+		x += w + 2*pad
+	}
+	if inked == 0 {
+		t.Error("WriteString(Hi!) drew nothing")
+	}
+
+	face := fnt.Face()
+	adv, ok := face.GlyphAdvance(' ')
+	if !ok || adv != fixed.I(2+fnt.GridPadding) {
+		t.Errorf("GlyphAdvance(space) = %v, %v, want %v, true", adv, ok, fixed.I(2+fnt.GridPadding))
+	}
+	if _, ok := face.GlyphAdvance('€'); ok {
+		t.Error("GlyphAdvance(€) ok = true, want false")
+	}
+}
+
+func TestImageFont_embeddedMetrics(t *testing.T) {
+	for _, f := range allImageFonts {
+		t.Run(f.Name, func(t *testing.T) {
+			fnt := *f
+			fnt.Metrics = nil
+			if err := fnt.Ready(); err != nil {
+				t.Fatal(err)
+			}
+			if got, want := len(fnt.Metrics), fnt.numChars(); got != want {
+				t.Errorf("len(Metrics) = %d, want %d", got, want)
+			}
+			if fnt.XWidth("i") >= fnt.XWidth("m") {
+				t.Errorf("XWidth(i) = %d, want less than XWidth(m) = %d", fnt.XWidth("i"), fnt.XWidth("m"))
+			}
+		})
+	}
+	f, err := LookupFont("microfont")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Metrics) != CharsetSz {
+		t.Errorf("registered microfont has %d metrics, want %d", len(f.Metrics), CharsetSz)
+	}
+}
@@ -0,0 +1,127 @@
+package fontpic
+
+import (
+	"errors"
+	"image"
+	"image/draw"
+	"math"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+
+	"github.com/rusq/fontpic/charset"
+)
+
+// rasterize.go contains the converter of the scalable fonts to FNT.
+
+// RasterOptions are the options for [Rasterize].
+type RasterOptions struct {
+	// Width and Height is the character cell size in pixels.
+	Width, Height int
+	// Charset is the code page of the resulting font, i.e. "866".  If empty,
+	// the character codes are mapped to the runes as is (Latin-1).
+	Charset string
+	// Threshold is the minimum coverage of the pixel to be set.  If zero,
+	// 0x80 is used.
+	Threshold uint8
+	// Size is the font size in pixels.  If zero, the size is chosen so that
+	// the ascent and descent of the font fit the cell height.
+	Size float64
+	// Hinting is the outline hinting.
+	Hinting font.Hinting
+}
+
+// Rasterize renders the characters of the scalable font to the FNT with the
+// fixed cell size.  The characters are aligned on the common baseline and
+// centred horizontally in the cell.  Characters that are not in the font are
+// left blank.  Use [FNT.Sample] to preview the result.
+func Rasterize(f *opentype.Font, opts RasterOptions) (*FNT, error) {
+	if opts.Width < 1 || opts.Height < 1 {
+		return nil, errors.New("invalid cell size")
+	}
+	if opts.Threshold == 0 {
+		opts.Threshold = 0x80
+	}
+	var cs charset.Charset
+	if opts.Charset != "" {
+		var ok bool
+		if cs, ok = charset.ByName(opts.Charset); !ok {
+			return nil, errors.New("unknown charset: " + opts.Charset)
+		}
+	}
+	if opts.Size == 0 {
+		size, err := fitSize(f, opts.Height)
+		if err != nil {
+			return nil, err
+		}
+		opts.Size = size
+	}
+	face, err := opentype.NewFace(f, &opentype.FaceOptions{
+		Size:    opts.Size,
+		DPI:     72,
+		Hinting: opts.Hinting,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer face.Close()
+
+	// the baseline is placed so that the font descent is at the bottom of
+	// the cell.
+	baseline := opts.Height - face.Metrics().Descent.Round()
+	fnt := &FNT{
+		Width:   opts.Width,
+		Height:  opts.Height,
+		Charset: opts.Charset,
+		Chars:   toChars(make([]byte, CharsetSz*charStride(opts.Width)*opts.Height), opts.Width, opts.Height),
+	}
+	cell := image.NewAlpha(image.Rect(0, 0, opts.Width, opts.Height))
+	for ch := range CharsetSz {
+		r := rune(ch)
+		if cs != "" {
+			r = cs.Rune(byte(ch))
+		}
+		adv, ok := face.GlyphAdvance(r)
+		if !ok {
+			continue
+		}
+		dot := fixed.Point26_6{
+			X: (fixed.I(opts.Width) - adv) / 2,
+			Y: fixed.I(baseline),
+		}
+		dr, mask, maskp, _, ok := face.Glyph(dot, r)
+		if !ok {
+			continue
+		}
+		clear(cell.Pix)
+		draw.DrawMask(cell, dr, image.Opaque, image.Point{}, mask, maskp, draw.Over)
+		for y := range opts.Height {
+			for x := range opts.Width {
+				if cell.AlphaAt(x, y).A >= opts.Threshold {
+					fnt.SetPixel(byte(ch), x, y, true)
+				}
+			}
+		}
+	}
+	return fnt, nil
+}
+
+// fitSize returns the font size in pixels, at which the ascent and the
+// descent of the font fit in height pixels.
+func fitSize(f *opentype.Font, height int) (float64, error) {
+	face, err := opentype.NewFace(f, &opentype.FaceOptions{
+		Size: float64(height),
+		DPI:  72,
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer face.Close()
+	m := face.Metrics()
+	total := float64(m.Ascent+m.Descent) / 64
+	if total <= 0 {
+		return float64(height), nil
+	}
+	return math.Floor(float64(height)*float64(height)/total*4) / 4, nil
+}
@@ -0,0 +1,68 @@
+package fontpic
+
+import (
+	"testing"
+
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/opentype"
+)
+
+func TestRasterize(t *testing.T) {
+	otf, err := opentype.Parse(gomono.TTF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		opts    RasterOptions
+		wantErr bool
+	}{
+		{"8x16 cp866", RasterOptions{Width: 8, Height: 16, Charset: "866"}, false},
+		{"12x24 latin", RasterOptions{Width: 12, Height: 24}, false},
+		{"unknown charset", RasterOptions{Width: 8, Height: 16, Charset: "1252"}, true},
+		{"no size", RasterOptions{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fnt, err := Rasterize(otf, tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Rasterize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if fnt.Width != tt.opts.Width || fnt.Height != tt.opts.Height {
+				t.Errorf("size = %dx%d", fnt.Width, fnt.Height)
+			}
+			if left, w := inkBounds(fnt.Width, fnt.Height, func(x, y int) bool { return fnt.Pixel(' ', x, y) }); w != 0 {
+				t.Errorf("space is not blank: %d, %d", left, w)
+			}
+			// 'A' must be inked, and it must sit on the baseline, not below
+			// the descender of 'g'.
+			if _, w := inkBounds(fnt.Width, fnt.Height, func(x, y int) bool { return fnt.Pixel('A', x, y) }); w == 0 {
+				t.Error("A is blank")
+			}
+			if lastRow(fnt, 'A') > lastRow(fnt, 'g') {
+				t.Errorf("A is below the g descender: %d > %d", lastRow(fnt, 'A'), lastRow(fnt, 'g'))
+			}
+			if tt.opts.Charset == "866" {
+				if _, w := inkBounds(fnt.Width, fnt.Height, func(x, y int) bool { return fnt.Pixel(0x96, x, y) }); w == 0 {
+					t.Error("Cyrillic Ц is blank")
+				}
+			}
+		})
+	}
+}
+
+// lastRow returns the last inked row of the character.
+func lastRow(f *FNT, ch byte) int {
+	last := -1
+	for y := range f.Height {
+		for x := range f.Width {
+			if f.Pixel(ch, x, y) {
+				last = y
+			}
+		}
+	}
+	return last
+}
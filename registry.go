@@ -0,0 +1,174 @@
+package fontpic
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// registry.go contains the registry of the named fonts: the embedded ones,
+// and the ones registered by the application.
+
+// FontInfo is the metadata of the registered font.
+type FontInfo struct {
+	// Name is the unique name of the font, i.e. "8x16" or "microfont_bold".
+	// The names are case insensitive.
+	Name   string
+	Family string
+	// Style is the style within the family, i.e. "Regular" or "Bold".
+	Style string
+	// Width and Height are the character cell size.
+	Width, Height int
+	Charset       string
+	License       string
+	// Source is where the font comes from, usually the URL.
+	Source string
+}
+
+// ErrFontNotFound is returned by the lookups, if there is no such font.
+var ErrFontNotFound = errors.New("font not found")
+
+type registeredFont struct {
+	info FontInfo
+	load func() (*FNT, error)
+}
+
+var registry = struct {
+	mu    sync.RWMutex
+	fonts map[string]*registeredFont // by the lower case name
+}{fonts: make(map[string]*registeredFont)}
+
+// RegisterFont registers the loaded font under info.Name, so that it can be
+// looked up by the name or by the family and style.  The cell size is taken
+// from the font, as well as the charset, unless it is set in the info.  The
+// family defaults to the name, and the style to "Regular".  It returns an
+// error, if the font with the same name is already registered.
+func RegisterFont(info FontInfo, f *FNT) error {
+	if f == nil {
+		return errors.New("font is nil")
+	}
+	info.Width, info.Height = f.Width, f.Height
+	if info.Charset == "" {
+		info.Charset = f.Charset
+	}
+	return register(info, func() (*FNT, error) { return f, nil })
+}
+
+// register adds the font with the loader to the registry.  The loader is
+// called once, on the first lookup.
+func register(info FontInfo, load func() (*FNT, error)) error {
+	if info.Name == "" {
+		return errors.New("font name is required")
+	}
+	if info.Family == "" {
+		info.Family = info.Name
+	}
+	if info.Style == "" {
+		info.Style = "Regular"
+	}
+	key := strings.ToLower(info.Name)
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	if _, ok := registry.fonts[key]; ok {
+		return fmt.Errorf("font %q is already registered", info.Name)
+	}
+	registry.fonts[key] = &registeredFont{info: info, load: sync.OnceValues(load)}
+	return nil
+}
+
+// Fonts returns the metadata of all registered fonts, sorted by the family,
+// the style and the name.
+func Fonts() []FontInfo {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	ret := make([]FontInfo, 0, len(registry.fonts))
+	for _, rf := range registry.fonts {
+		ret = append(ret, rf.info)
+	}
+	slices.SortFunc(ret, func(a, b FontInfo) int {
+		return cmp.Or(
+			strings.Compare(a.Family, b.Family),
+			strings.Compare(a.Style, b.Style),
+			strings.Compare(a.Name, b.Name),
+		)
+	})
+	return ret
+}
+
+// LookupFont returns the registered font by name.
+func LookupFont(name string) (*FNT, error) {
+	registry.mu.RLock()
+	rf, ok := registry.fonts[strings.ToLower(name)]
+	registry.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrFontNotFound, name)
+	}
+	return rf.load()
+}
+
+// LookupFamily returns the registered font by the family and style names,
+// which are case insensitive.  The empty style is "Regular".
+func LookupFamily(family, style string) (*FNT, error) {
+	if style == "" {
+		style = "Regular"
+	}
+	for _, fi := range Fonts() {
+		if strings.EqualFold(fi.Family, family) && strings.EqualFold(fi.Style, style) {
+			return LookupFont(fi.Name)
+		}
+	}
+	return nil, fmt.Errorf("%w: %s %s", ErrFontNotFound, family, style)
+}
+
+const (
+	keyrusLicense    = "Freeware, (c) Dmitry Gurtyak"
+	keyrusSource     = "https://en.wikipedia.org/wiki/KeyRus"
+	microfontLicense = "GPL-2.0-or-later, mibi88"
+	microfontSource  = "https://git.planet-casio.com/mibi88/microfont"
+)
+
+// builtinFonts are the embedded fonts.
+var builtinFonts = []struct {
+	info FontInfo
+	load func() (*FNT, error)
+}{
+	{FontInfo{Name: "8x8", Family: "KeyRus 8x8", Width: 8, Height: 8, Charset: "866", License: keyrusLicense, Source: keyrusSource}, fntLoader(Fnt8x8)},
+	{FontInfo{Name: "8x14", Family: "KeyRus 8x14", Width: 8, Height: 14, Charset: "866", License: keyrusLicense, Source: keyrusSource}, fntLoader(Fnt8x14)},
+	{FontInfo{Name: "8x16", Family: "KeyRus 8x16", Width: 8, Height: 16, Charset: "866", License: keyrusLicense, Source: keyrusSource}, fntLoader(Fnt8x16)},
+	{FontInfo{Name: "robotron", Family: "Robotron", Width: 9, Height: 18, License: "(c) Nikita Zimin", Source: "https://github.com/nzeemin/escparser"}, func() (*FNT, error) {
+		return FaceToFnt(LoadFaceRobotron()), nil
+	}},
+	{FontInfo{Name: "microfont", Family: "Microfont", Width: 5, Height: 5, License: microfontLicense, Source: microfontSource}, imageFontLoader(&IFMicrofont)},
+	{FontInfo{Name: "microfont_bold", Family: "Microfont", Style: "Bold", Width: 5, Height: 5, License: microfontLicense, Source: microfontSource}, imageFontLoader(&IFMicrofontBold)},
+	{FontInfo{Name: "microfont_italic", Family: "Microfont", Style: "Italic", Width: 5, Height: 5, License: microfontLicense, Source: microfontSource}, imageFontLoader(&IFMicrofontItalic)},
+	{FontInfo{Name: "milifont", Family: "Milifont", Width: 4, Height: 6, License: microfontLicense, Source: microfontSource}, imageFontLoader(&IFMiliFont)},
+	{FontInfo{Name: "stupid_simple", Family: "Stupid Simple", Width: 6, Height: 6, License: microfontLicense, Source: microfontSource}, imageFontLoader(&IFStupidSimple)},
+	{FontInfo{Name: "stupid_simple_bold", Family: "Stupid Simple", Style: "Bold", Width: 6, Height: 6, License: microfontLicense, Source: microfontSource}, imageFontLoader(&IFStupidSimpleBold)},
+	{FontInfo{Name: "stupid_simple_italic", Family: "Stupid Simple", Style: "Italic", Width: 6, Height: 6, License: microfontLicense, Source: microfontSource}, imageFontLoader(&IFStupidSimpleItalic)},
+}
+
+func fntLoader(f *FNT) func() (*FNT, error) {
+	return func() (*FNT, error) { return f, nil }
+}
+
+// imageFontLoader returns the loader, that converts the image font to FNT,
+// loading it, if it is the embedded font.
+func imageFontLoader(f *ImageFont) func() (*FNT, error) {
+	return func() (*FNT, error) {
+		if err := f.Ready(); err != nil {
+			return nil, err
+		}
+		return f.ToFnt(), nil
+	}
+}
+
+func init() {
+	for _, bf := range builtinFonts {
+		if err := register(bf.info, bf.load); err != nil {
+			panic(err)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package fontpic
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestFonts_builtin(t *testing.T) {
+	fonts := Fonts()
+	if len(fonts) < len(builtinFonts) {
+		t.Fatalf("got %d fonts, want at least %d", len(fonts), len(builtinFonts))
+	}
+	for _, fi := range fonts {
+		f, err := LookupFont(fi.Name)
+		if err != nil {
+			t.Fatalf("LookupFont(%q): %v", fi.Name, err)
+		}
+		// the metadata must match the font without loading it.
+		if f.Width != fi.Width || f.Height != fi.Height || f.Charset != fi.Charset {
+			t.Errorf("%s: font is %dx%d %q, info is %dx%d %q", fi.Name, f.Width, f.Height, f.Charset, fi.Width, fi.Height, fi.Charset)
+		}
+		if fi.Family == "" || fi.Style == "" || fi.License == "" || fi.Source == "" {
+			t.Errorf("%s: incomplete info %+v", fi.Name, fi)
+		}
+	}
+}
+
+func TestLookupFont(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    *FNT
+		wantErr error
+	}{
+		{"8x16", Fnt8x16, nil},
+		{"8X14", Fnt8x14, nil},
+		{"comic_sans", nil, ErrFontNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LookupFont(tt.name)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("LookupFont() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("LookupFont() = %p, want %p", got, tt.want)
+			}
+		})
+	}
+	// the converted fonts are cached.
+	a, _ := LookupFont("microfont")
+	b, _ := LookupFont("microfont")
+	if a == nil || a != b {
+		t.Error("LookupFont(microfont) is not cached")
+	}
+}
+
+func TestLookupFamily(t *testing.T) {
+	tests := []struct {
+		family, style string
+		wantName      string
+		wantErr       bool
+	}{
+		{"Microfont", "", "microfont", false},
+		{"microfont", "bold", "microfont_bold", false},
+		{"Stupid Simple", "Italic", "stupid_simple_italic", false},
+		{"KeyRus 8x8", "Regular", "8x8", false},
+		{"Milifont", "Bold", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.family+" "+tt.style, func(t *testing.T) {
+			got, err := LookupFamily(tt.family, tt.style)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LookupFamily() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			want, _ := LookupFont(tt.wantName)
+			if got != want {
+				t.Errorf("LookupFamily() is not %s", tt.wantName)
+			}
+		})
+	}
+}
+
+func TestRegisterFont(t *testing.T) {
+	f := lFont()
+	defer func() {
+		registry.mu.Lock()
+		delete(registry.fonts, "test_l")
+		registry.mu.Unlock()
+	}()
+	if err := RegisterFont(FontInfo{Name: "Test_L", License: "none"}, f); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LookupFont("test_l")
+	if err != nil || got != f {
+		t.Fatalf("LookupFont() = %v, %v", got, err)
+	}
+	if got, err := LookupFamily("test_l", ""); err != nil || got != f {
+		t.Errorf("LookupFamily() = %v, %v", got, err)
+	}
+	var info FontInfo
+	for _, fi := range Fonts() {
+		if strings.EqualFold(fi.Name, "test_l") {
+			info = fi
+		}
+	}
+	if want := (FontInfo{Name: "Test_L", Family: "Test_L", Style: "Regular", Width: 3, Height: 2, License: "none"}); info != want {
+		t.Errorf("info = %+v, want %+v", info, want)
+	}
+
+	if err := RegisterFont(FontInfo{Name: "TEST_L"}, f); err == nil {
+		t.Error("duplicate name is registered")
+	}
+	if err := RegisterFont(FontInfo{}, f); err == nil {
+		t.Error("empty name is registered")
+	}
+	if err := RegisterFont(FontInfo{Name: "nil"}, nil); err == nil {
+		t.Error("nil font is registered")
+	}
+}
@@ -35,8 +35,16 @@ type Canvas struct {
 	Foreground color.Color // Color to use for the font
 	Font       *FNT        // Font to use
 	Spacing    image.Point // Spacing between characters.
-	Scale      image.Point // scaling factor (not used yet)
-	image      draw.Image
+	// Scale is the integer scale of the document size of the SVG output, see
+	// [Canvas.WriteSVG], and of the default size of the empty canvas.  The
+	// raster image is not scaled, use [Enlarge] for that.
+	Scale   image.Point
+	Effects Effects // Outline, shadow and glow effects.
+	// Vertical stacks the characters of each line top to bottom, and the
+	// lines are placed left to right.
+	Vertical bool
+	image    draw.Image
+	origin   image.Point // offset of the text, leaves room for effects.
 }
 
 // NewCanvas creates the new canvas with the default font.
@@ -60,9 +68,18 @@ func (c *Canvas) WithSpacing(x, y int) *Canvas {
 	return c
 }
 
+// WithVertical enables or disables the vertical text mode.  Combined with
+// the rotated font, i.e. [FNT.Rotate90], it renders the text for the vertical
+// signs.
+func (c *Canvas) WithVertical(vertical bool) *Canvas {
+	c.Vertical = vertical
+	return c
+}
+
 func (c *Canvas) WithSize(w, h int) *Canvas {
 	c.Width = w
 	c.Height = h
+	c.origin = image.Point{}
 	return c
 }
 
@@ -100,23 +117,122 @@ const (
 // of text.
 func (c *Canvas) CalcSize(lines [][]byte) *Canvas {
 	c.ensure()
+	return c.calcSize(c.toGlyphs(lines))
+}
+
+func (c *Canvas) calcSize(lines [][]glyph) *Canvas {
 	if len(lines) == 0 {
 		c.Width = DefaultWidth * c.Scale.X
 		c.Height = DefaultHeight * c.Scale.Y // 4:3
 		return c
 	}
-	maxLineLen := 0
+	var maxLineWidth, across int
 	for _, line := range lines {
-		if len(line) > maxLineLen {
-			maxLineLen = len(line)
+		maxLineWidth = max(maxLineWidth, c.lineWidth(line))
+		if c.Vertical {
+			across += c.columnWidth(line) + c.Spacing.X
+		} else {
+			ascent, descent := c.lineMetrics(line)
+			across += ascent + descent + c.Spacing.Y
 		}
 	}
-	// account for spacing
-	c.Width = (maxLineLen * c.Font.Width) + (c.Spacing.X * maxLineLen)
-	c.Height = (len(lines) * c.Font.Height) + (c.Spacing.Y * len(lines))
+	if c.Vertical {
+		c.Width = across
+		c.Height = maxLineWidth
+	} else {
+		c.Width = maxLineWidth
+		c.Height = across
+	}
+	// leave room for the effects around the text.
+	lt, rb := c.Effects.margin()
+	c.origin = lt
+	c.Width += lt.X + rb.X
+	c.Height += lt.Y + rb.Y
 	return c
 }
 
+// glyph is a character with the font it is rendered with.  The fg and bg
+// colours override the canvas colours, if set.
+type glyph struct {
+	ch     byte
+	font   *FNT
+	fg, bg color.Color
+}
+
+// toGlyphs converts the lines of text to the lines of glyphs of the canvas
+// font.
+func (c *Canvas) toGlyphs(lines [][]byte) [][]glyph {
+	var ret = make([][]glyph, len(lines))
+	for i, line := range lines {
+		ret[i] = make([]glyph, len(line))
+		for j, ch := range line {
+			ret[i][j] = glyph{ch: ch, font: c.Font}
+		}
+	}
+	return ret
+}
+
+// advance returns the distance from the glyph g to the next glyph, accounting
+// for spacing and kerning.  Kerning applies only to the adjacent glyphs of
+// the same font.
+func (c *Canvas) advance(g glyph, next *glyph) int {
+	adv := g.font.Advance(g.ch) + c.Spacing.X
+	if next != nil && next.font == g.font {
+		adv += g.font.Kern(g.ch, next.ch)
+	}
+	return adv
+}
+
+// lineWidth returns the width of the line in pixels, accounting for spacing.
+// In the vertical mode, it is the height of the column of characters.
+func (c *Canvas) lineWidth(line []glyph) int {
+	var w int
+	for i := range line {
+		if c.Vertical {
+			w += line[i].font.Height + c.Spacing.Y
+		} else {
+			w += c.advance(line[i], nextGlyph(line, i))
+		}
+	}
+	return w
+}
+
+// lineMetrics returns the ascent and the descent of the line, the largest of
+// the glyph fonts, so that the glyphs of the fonts of different height share
+// the baseline.  The empty line has the metrics of the canvas font.
+func (c *Canvas) lineMetrics(line []glyph) (ascent, descent int) {
+	if len(line) == 0 {
+		return c.Font.Height - c.Font.descent(), c.Font.descent()
+	}
+	for _, g := range line {
+		ascent = max(ascent, g.font.Height-g.font.descent())
+		descent = max(descent, g.font.descent())
+	}
+	return ascent, descent
+}
+
+// columnWidth returns the width of the line in the vertical mode, that is
+// the width of the widest glyph font.  The empty line has the width of the
+// canvas font.
+func (c *Canvas) columnWidth(line []glyph) int {
+	if len(line) == 0 {
+		return c.Font.Width
+	}
+	var w int
+	for _, g := range line {
+		w = max(w, g.font.Width)
+	}
+	return w
+}
+
+// nextGlyph returns the glyph following the i-th glyph or nil.
+func nextGlyph(line []glyph, i int) *glyph {
+	if i+1 < len(line) {
+		return &line[i+1]
+	}
+	return nil
+}
+
 func (c *Canvas) WithBackground(bg color.Color) *Canvas {
 	c.Background = bg
 	return c
@@ -131,6 +247,7 @@ func (c *Canvas) WithImage(img draw.Image) *Canvas {
 	c.image = img
 	c.Width = img.Bounds().Dx()
 	c.Height = img.Bounds().Dy()
+	c.origin = image.Point{}
 	return c
 }
 
@@ -154,10 +271,10 @@ func (c *Canvas) RenderTextAt(text []byte, at image.Point) *Canvas {
 	return c.renderTextAt(text, at)
 }
 
-func (c *Canvas) init(lines [][]byte) {
+func (c *Canvas) init(lines [][]glyph) {
 	c.ensure()
 	if c.Width == 0 || c.Height == 0 {
-		c.CalcSize(lines)
+		c.calcSize(lines)
 	}
 	if c.image == nil {
 		c.image = image.NewRGBA(image.Rect(0, 0, c.Width, c.Height))
@@ -169,35 +286,121 @@ func (c *Canvas) init(lines [][]byte) {
 // that lines are separated by \n, and wraps at the end of the line.
 // It also replaces tabs with 8 spaces.
 func (c *Canvas) renderTextAt(text []byte, at image.Point) *Canvas {
+	return c.renderAt(textLines(text), at)
+}
+
+// textLines splits the text into lines and replaces tabs with 8 spaces.
+func textLines(text []byte) [][]byte {
 	lines := bytes.Split(text, []byte("\n"))
 	for i := range lines {
 		lines[i] = bytes.ReplaceAll(bytes.TrimRight(lines[i], "\r\n"), []byte("\t"), []byte("        "))
 	}
-	return c.renderAt(lines, at)
+	return lines
 }
 
 // renderAt renders the lines at the specified location.
 func (c *Canvas) renderAt(lines [][]byte, at image.Point) *Canvas {
+	c.ensure()
+	return c.renderGlyphsAt(c.toGlyphs(lines), at)
+}
+
+// renderGlyphsAt renders the lines of glyphs at the specified location.
+func (c *Canvas) renderGlyphsAt(lines [][]glyph, at image.Point) *Canvas {
 	c.init(lines)
-	for y, line := range lines {
-		for x, ch := range line {
-			RenderCharAt(
-				c.image,
-				image.Point{
-					X: at.X + (x * c.Font.Width) + (x * c.Spacing.X),
-					Y: at.Y + (y * c.Font.Height) + (y * c.Spacing.Y),
-				},
-				c.Font.Width,
-				c.Font.Height,
-				c.Font.Chars[ch],
-				c.Foreground,
-				c.Background,
-			)
+	at = at.Add(c.origin)
+	// with effects, the text is rendered to the coverage mask first, and the
+	// mask is then used to draw the effects and the text.
+	var (
+		dst  draw.Image = c.image
+		fg              = c.Foreground
+		mask *image.Alpha
+	)
+	if c.Effects.enabled() {
+		mask = image.NewAlpha(c.image.Bounds())
+		dst, fg = mask, color.Opaque
+	}
+	glyphs, fills := c.layout(lines, at)
+	// the background is painted first, so that the kerned characters don't
+	// overwrite each other.
+	for _, f := range fills {
+		draw.Draw(c.image, f.Rectangle, image.NewUniform(colorOr(f.color, c.Background)), image.Point{}, draw.Src)
+	}
+	for _, g := range glyphs {
+		if mask != nil {
+			g.font.drawChar(dst, g.at, g.ch, fg, nil)
+		} else {
+			g.font.drawChar(dst, g.at, g.ch, colorOr(g.fg, fg), nil)
+		}
+	}
+	if mask != nil {
+		c.Effects.draw(c.image, mask, c.Foreground)
+		// the effects are drawn in the canvas colour, the glyphs with their
+		// own colour are painted over.
+		for _, g := range glyphs {
+			if g.fg != nil {
+				g.font.drawChar(c.image, g.at, g.ch, g.fg, nil)
+			}
 		}
 	}
 	return c
 }
 
+// placedGlyph is a glyph with its position on the canvas.
+type placedGlyph struct {
+	glyph
+	at image.Point
+}
+
+// fillRect is the rectangle of the background.
+type fillRect struct {
+	image.Rectangle
+	color color.Color // nil is the canvas background.
+}
+
+// layout positions the lines of glyphs, starting at the given point.  It
+// returns the positioned glyphs and the background rectangles: the lines,
+// which are the columns in the vertical mode, and then the glyphs with their
+// own background.  In the horizontal mode, the glyphs are aligned on the
+// baseline of the line.
+func (c *Canvas) layout(lines [][]glyph, at image.Point) ([]placedGlyph, []fillRect) {
+	var (
+		glyphs []placedGlyph
+		fills  = make([]fillRect, 0, len(lines))
+		pos    = at
+	)
+	for _, line := range lines {
+		pt := pos
+		if c.Vertical {
+			width := c.columnWidth(line)
+			fills = append(fills, fillRect{Rectangle: image.Rect(pt.X, pt.Y, pt.X+width, pt.Y+c.lineWidth(line))})
+			for _, g := range line {
+				adv := g.font.Height + c.Spacing.Y
+				if g.bg != nil {
+					fills = append(fills, fillRect{image.Rect(pt.X, pt.Y, pt.X+width, pt.Y+adv), g.bg})
+				}
+				glyphs = append(glyphs, placedGlyph{glyph: g, at: pt})
+				pt.Y += adv
+			}
+			pos.X += width + c.Spacing.X
+			continue
+		}
+		ascent, descent := c.lineMetrics(line)
+		height := ascent + descent
+		fills = append(fills, fillRect{Rectangle: image.Rect(pt.X, pt.Y, pt.X+c.lineWidth(line), pt.Y+height)})
+		for x, g := range line {
+			adv := c.advance(g, nextGlyph(line, x))
+			if g.bg != nil {
+				fills = append(fills, fillRect{image.Rect(pt.X, pt.Y, pt.X+adv, pt.Y+height), g.bg})
+			}
+			top := ascent - (g.font.Height - g.font.descent())
+			glyphs = append(glyphs, placedGlyph{glyph: g, at: image.Pt(pt.X, pt.Y+top)})
+			pt.X += adv
+		}
+		pos.Y += height + c.Spacing.Y
+	}
+	return glyphs, fills
+}
+
 func (c *Canvas) Image() draw.Image {
 	if c.image == nil {
 		c.init(nil)
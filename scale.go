@@ -0,0 +1,259 @@
+package fontpic
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"strings"
+
+	xdraw "golang.org/x/image/draw"
+)
+
+// scale.go contains the pixel-art upscalers for the glyphs and images.
+
+// Scaler is the pixel-art upscaling algorithm.
+type Scaler int
+
+const (
+	// ScaleNearest is the nearest-neighbour 2x scaling.
+	ScaleNearest Scaler = iota
+	// Scale2x is the Scale2x (AdvMAME2x, EPX) 2x scaling.
+	Scale2x
+	// Scale3x is the Scale3x (AdvMAME3x) 3x scaling.
+	Scale3x
+	// ScaleEagle is the Eagle 2x scaling.  It erodes the isolated pixels, so
+	// it works best with the bold fonts.
+	ScaleEagle
+	// ScaleSFX is the 2x scaling, similar in spirit to 2xSaI and hq2x: it is
+	// Scale2x with the additional checks of the wider neighbourhood, that
+	// keep the shallow diagonals smooth without blunting the corners.  It is
+	// based on Scale2xSFX.
+	ScaleSFX
+)
+
+// Factor returns the scaling factor of the scaler.
+func (s Scaler) Factor() int {
+	if s == Scale3x {
+		return 3
+	}
+	return 2
+}
+
+func (s Scaler) String() string {
+	switch s {
+	case ScaleNearest:
+		return "nearest"
+	case Scale2x:
+		return "scale2x"
+	case Scale3x:
+		return "scale3x"
+	case ScaleEagle:
+		return "eagle"
+	case ScaleSFX:
+		return "sfx"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseScaler returns the scaler by its name, as returned by
+// [Scaler.String].
+func ParseScaler(s string) (Scaler, error) {
+	for sc := ScaleNearest; sc <= ScaleSFX; sc++ {
+		if strings.EqualFold(s, sc.String()) {
+			return sc, nil
+		}
+	}
+	return 0, errors.New("unknown scaler: " + s)
+}
+
+// grid is a two-dimensional array of pixels.
+type grid[T comparable] struct {
+	w, h int
+	pix  []T
+	// outside is the value of the pixels outside of the grid, unless clamp
+	// is set, in which case the nearest edge pixel is returned.
+	outside T
+	clamp   bool
+}
+
+func newGrid[T comparable](w, h int) grid[T] {
+	return grid[T]{w: w, h: h, pix: make([]T, w*h)}
+}
+
+func (g grid[T]) at(x, y int) T {
+	if x < 0 || y < 0 || x >= g.w || y >= g.h {
+		if !g.clamp {
+			return g.outside
+		}
+		x, y = min(max(x, 0), g.w-1), min(max(y, 0), g.h-1)
+	}
+	return g.pix[y*g.w+x]
+}
+
+func (g grid[T]) set(x, y int, v T) {
+	g.pix[y*g.w+x] = v
+}
+
+// upscale scales the grid with the scaler s.
+func upscale[T comparable](src grid[T], s Scaler) grid[T] {
+	k := s.Factor()
+	dst := newGrid[T](src.w*k, src.h*k)
+	for y := range src.h {
+		for x := range src.w {
+			// A B C
+			// D E F
+			// G H I
+			var (
+				a, b, c = src.at(x-1, y-1), src.at(x, y-1), src.at(x+1, y-1)
+				d, e, f = src.at(x-1, y), src.at(x, y), src.at(x+1, y)
+				g, h, i = src.at(x-1, y+1), src.at(x, y+1), src.at(x+1, y+1)
+			)
+			var out []T
+			switch s {
+			case Scale2x:
+				out = []T{e, e, e, e}
+				if b != h && d != f {
+					out[0] = pick(d == b, d, e)
+					out[1] = pick(b == f, f, e)
+					out[2] = pick(d == h, d, e)
+					out[3] = pick(h == f, f, e)
+				}
+			case Scale3x:
+				out = []T{e, e, e, e, e, e, e, e, e}
+				if b != h && d != f {
+					out[0] = pick(d == b, d, e)
+					out[1] = pick((d == b && e != c) || (b == f && e != a), b, e)
+					out[2] = pick(b == f, f, e)
+					out[3] = pick((d == b && e != g) || (d == h && e != a), d, e)
+					out[5] = pick((b == f && e != i) || (h == f && e != c), f, e)
+					out[6] = pick(d == h, d, e)
+					out[7] = pick((d == h && e != i) || (h == f && e != g), h, e)
+					out[8] = pick(h == f, f, e)
+				}
+			case ScaleEagle:
+				out = []T{
+					pick(a == b && b == d, a, e),
+					pick(b == c && c == f, c, e),
+					pick(d == g && g == h, g, e),
+					pick(f == i && i == h, i, e),
+				}
+			case ScaleSFX:
+				// the pixels two steps away from the centre, beyond B, D, F
+				// and H.
+				var (
+					bb, dd = src.at(x, y-2), src.at(x-2, y)
+					ff, hh = src.at(x+2, y), src.at(x, y+2)
+				)
+				out = []T{
+					sfxCorner(e, b, d, f, h, a, c, g, bb, dd),
+					sfxCorner(e, f, b, h, d, c, i, a, ff, bb),
+					sfxCorner(e, d, h, b, f, g, a, i, dd, hh),
+					sfxCorner(e, h, f, d, b, i, g, c, hh, ff),
+				}
+			default:
+				out = []T{e, e, e, e}
+			}
+			for n, v := range out {
+				dst.set(x*k+n%k, y*k+n/k, v)
+			}
+		}
+	}
+	return dst
+}
+
+// sfxCorner returns the value of the output corner pixel between the
+// neighbours p and q of the centre pixel e.  The r and s are the neighbours
+// opposite to p and q, diag is the diagonal pixel between p and q, and d1,
+// d2 are the diagonal pixels adjacent to p and q respectively.  The far1 and
+// far2 are the pixels two steps from the centre beyond p and q.
+//
+// For the top-left corner: p=B, q=D, r=F, s=H, diag=A, d1=C, d2=G, far1 is
+// above B and far2 is left of D.
+func sfxCorner[T comparable](e, p, q, r, s, diag, d1, d2, far1, far2 T) T {
+	if p != q {
+		return e
+	}
+	if p != r && q != s && (e != diag || e == d1 || e == d2 || diag == far1 || diag == far2) {
+		return p
+	}
+	if d1 == e && d1 != far1 && diag != e {
+		return p
+	}
+	if e == d2 && diag != e && d2 != far2 {
+		return p
+	}
+	return e
+}
+
+func pick[T any](cond bool, a, b T) T {
+	if cond {
+		return a
+	}
+	return b
+}
+
+// Upscale returns the copy of the font scaled up with the scaler s.  For
+// example, Scale2x turns the 8x16 font into the 16x32 font, and Scale3x into
+// 24x48.  The metrics and the kerning are scaled accordingly.
+func (f *FNT) Upscale(s Scaler) *FNT {
+	var glyphs [CharsetSz]grid[bool]
+	for ch := range CharsetSz {
+		src := newGrid[bool](f.Width, f.Height)
+		for y := range f.Height {
+			for x := range f.Width {
+				src.set(x, y, f.Pixel(byte(ch), x, y))
+			}
+		}
+		glyphs[ch] = upscale(src, s)
+	}
+	k := s.Factor()
+	nf := f.derive(f.Width*k, f.Height*k, func(ch byte, x, y int) bool {
+		return glyphs[ch].at(x, y)
+	})
+	for i := range nf.Metrics {
+		nf.Metrics[i].Left *= k
+		nf.Metrics[i].Advance *= k
+	}
+	if f.Kerning != nil {
+		nf.Kerning = make(Kerning, len(f.Kerning))
+		for p, v := range f.Kerning {
+			nf.Kerning[p] = v * k
+		}
+	}
+	return nf
+}
+
+// UpscaleImage returns the image scaled up with the scaler s.  It is meant
+// for the images of the rendered text with a few distinct colours, as the
+// pixels are compared for the exact match.
+func UpscaleImage(img image.Image, s Scaler) *image.RGBA {
+	b := img.Bounds()
+	src := newGrid[color.RGBA](b.Dx(), b.Dy())
+	src.clamp = true
+	for y := range src.h {
+		for x := range src.w {
+			src.set(x, y, color.RGBAModel.Convert(img.At(b.Min.X+x, b.Min.Y+y)).(color.RGBA))
+		}
+	}
+	dst := upscale(src, s)
+	ret := image.NewRGBA(image.Rect(0, 0, dst.w, dst.h))
+	for y := range dst.h {
+		for x := range dst.w {
+			ret.SetRGBA(x, y, dst.at(x, y))
+		}
+	}
+	return ret
+}
+
+// Enlarge returns the image scaled k times with the nearest neighbour
+// interpolation.  If k is less than 2, the image is returned as is.
+func Enlarge(img image.Image, k int) image.Image {
+	if k <= 1 {
+		return img
+	}
+	b := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx()*k, b.Dy()*k))
+	xdraw.NearestNeighbor.Scale(dst, dst.Bounds(), img, b, xdraw.Src, nil)
+	return dst
+}
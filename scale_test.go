@@ -0,0 +1,105 @@
+package fontpic
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// diagFont returns a 2x2 font with the character 'D' being a diagonal.
+func diagFont() *FNT {
+	f := &FNT{Width: 2, Height: 2}
+	f.Chars = toChars(make([]byte, CharsetSz*2), 2, 2)
+	f.SetPixel('D', 0, 0, true)
+	f.SetPixel('D', 1, 1, true)
+	return f
+}
+
+func TestFNT_Upscale(t *testing.T) {
+	tests := []struct {
+		name string
+		s    Scaler
+		want string
+	}{
+		{"nearest", ScaleNearest, "##..\n##..\n..##\n..##\n"},
+		{"scale2x", Scale2x, "##..\n###.\n.###\n..##\n"},
+		// eagle erodes the isolated pixels
+		{"eagle", ScaleEagle, "....\n.#..\n..#.\n....\n"},
+		{"sfx", ScaleSFX, "#...\n.##.\n.##.\n...#\n"},
+		{"scale3x", Scale3x, "###...\n###...\n####..\n..####\n...###\n...###\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := diagFont().Upscale(tt.s)
+			if got.Width != 2*tt.s.Factor() || got.Height != 2*tt.s.Factor() {
+				t.Errorf("size = %dx%d", got.Width, got.Height)
+			}
+			if g := glyphString(got, 'D'); g != tt.want {
+				t.Errorf("glyph:\n%s\nwant:\n%s", g, tt.want)
+			}
+		})
+	}
+}
+
+func TestFNT_Upscale_metrics(t *testing.T) {
+	fnt := Fnt8x16.Proportional(3, 1).WithKerning(Kerning{{'A', 'V'}: -1}).Upscale(Scale3x)
+	if fnt.Width != 24 || fnt.Height != 48 {
+		t.Errorf("size = %dx%d, want 24x48", fnt.Width, fnt.Height)
+	}
+	if got := fnt.Advance(' '); got != 9 {
+		t.Errorf("Advance(' ') = %d, want 9", got)
+	}
+	if got := fnt.Kern('A', 'V'); got != -3 {
+		t.Errorf("Kern() = %d, want -3", got)
+	}
+}
+
+func TestUpscaleImage(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 3, 2))
+	src.Set(1, 1, color.White)
+	got := UpscaleImage(src, Scale2x)
+	if got.Bounds() != image.Rect(0, 0, 6, 4) {
+		t.Fatalf("Bounds() = %v", got.Bounds())
+	}
+	if !colEq(got.At(2, 2), color.White) || !colEq(got.At(3, 3), color.White) {
+		t.Error("the white pixel was not scaled")
+	}
+}
+
+func TestParseScaler(t *testing.T) {
+	for _, s := range []Scaler{ScaleNearest, Scale2x, Scale3x, ScaleEagle, ScaleSFX} {
+		got, err := ParseScaler(s.String())
+		if err != nil || got != s {
+			t.Errorf("ParseScaler(%q) = %v, %v", s, got, err)
+		}
+	}
+	if _, err := ParseScaler("hq4x"); err == nil {
+		t.Error("ParseScaler(hq4x) expected error")
+	}
+}
+
+func TestEnlarge(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	src.Set(1, 0, color.White)
+	tests := []struct {
+		name string
+		k    int
+		want image.Point
+	}{
+		{"zero", 0, image.Pt(2, 1)},
+		{"one", 1, image.Pt(2, 1)},
+		{"three", 3, image.Pt(6, 3)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Enlarge(src, tt.k)
+			if got.Bounds().Size() != tt.want {
+				t.Fatalf("size = %v, want %v", got.Bounds().Size(), tt.want)
+			}
+			k := max(tt.k, 1)
+			if !colEq(got.At(k, k-1), color.White) || !colEq(got.At(k-1, 0), color.Transparent) {
+				t.Errorf("pixels are not scaled")
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package fontpic
+
+import (
+	"cmp"
+	"slices"
+)
+
+// scan.go contains the scanner of the binary files for the 8xN font tables,
+// i.e. in the video BIOS dumps and the old programs.
+
+// Candidate is the plausible font table found in the binary.
+type Candidate struct {
+	// Offset is the offset of the table in the binary.
+	Offset int
+	Height int
+	// Score is the confidence of the candidate, from 0 to 1.  It is mostly
+	// the similarity of the digits and the letter 'A' to the reference font,
+	// with the bonus for the blank character 0 and the full block 0xdb, as
+	// in the PC code pages.  The bonus helps to find the exact offset, as
+	// the table shifted by a row or two also looks plausible.
+	Score float64
+	Font  *FNT
+}
+
+// ScanOptions are the options of [ScanFonts].
+type ScanOptions struct {
+	// Heights are the character heights to look for.  If empty, 8, 14 and
+	// 16 are used.
+	Heights []int
+	// MinScore is the minimal score of the candidate.  If zero, 0.5 is used.
+	MinScore float64
+}
+
+// scanShapes are the characters compared with the reference font.
+var scanShapes = []byte("0123456789A")
+
+// ScanFonts searches the binary data for the tables of 256 characters 8
+// pixels wide.  The table is considered plausible, if the space is blank,
+// the digits and the letters are not, the density of the pixels in the
+// printable characters is reasonable, and the digits and the letter 'A' look
+// like the ones of the default font.  The overlapping candidates are
+// resolved in favour of the higher score.  The candidates are returned in the
+// order of the offsets.  Use [FNT.Sample] on the candidate font for the
+// preview.
+func ScanFonts(data []byte, opts ScanOptions) []Candidate {
+	if len(opts.Heights) == 0 {
+		opts.Heights = []int{8, 14, 16}
+	}
+	if opts.MinScore == 0 {
+		opts.MinScore = 0.5
+	}
+	var found []Candidate
+	for _, h := range opts.Heights {
+		if h < 1 {
+			continue
+		}
+		ref := scanReference(h)
+		for off := 0; off+CharsetSz*h <= len(data); off++ {
+			table := data[off : off+CharsetSz*h]
+			if !plausibleTable(table, h) {
+				continue
+			}
+			fnt, err := ToFnt(table, chrWidth)
+			if err != nil {
+				continue
+			}
+			if score := tableScore(fnt, ref); score >= opts.MinScore {
+				found = append(found, Candidate{Offset: off, Height: h, Score: score, Font: fnt})
+			}
+		}
+	}
+	// keep the best of the overlapping candidates.
+	slices.SortStableFunc(found, func(a, b Candidate) int {
+		return cmp.Compare(b.Score, a.Score)
+	})
+	var ret []Candidate
+	for _, c := range found {
+		overlaps := slices.ContainsFunc(ret, func(k Candidate) bool {
+			return c.Offset < k.Offset+CharsetSz*k.Height && k.Offset < c.Offset+CharsetSz*c.Height
+		})
+		if !overlaps {
+			ret = append(ret, c)
+		}
+	}
+	slices.SortFunc(ret, func(a, b Candidate) int {
+		return cmp.Compare(a.Offset, b.Offset)
+	})
+	return ret
+}
+
+// plausibleTable reports whether the raw 8xh font table has the blank space,
+// non-blank alphanumeric characters and the reasonable pixel density.
+func plausibleTable(table []byte, h int) bool {
+	glyph := func(ch byte) []byte {
+		return table[int(ch)*h : (int(ch)+1)*h]
+	}
+	for _, b := range glyph(' ') {
+		if b != 0 {
+			return false
+		}
+	}
+	for _, r := range [][2]byte{{'0', '9'}, {'A', 'Z'}, {'a', 'z'}} {
+		for ch := r[0]; ch <= r[1]; ch++ {
+			if !slices.ContainsFunc(glyph(ch), func(b byte) bool { return b != 0 }) {
+				return false
+			}
+		}
+	}
+	var set int
+	for _, b := range table['!'*h : ('~'+1)*h] {
+		set += popcount(b)
+	}
+	density := float64(set) / float64(('~'-'!'+1)*h*chrWidth)
+	return density >= 0.05 && density <= 0.6
+}
+
+func popcount(b byte) int {
+	var n int
+	for ; b != 0; b &= b - 1 {
+		n++
+	}
+	return n
+}
+
+// scanReference returns the reference font of the given height: one of the
+// embedded fonts, or the 8x16 font resampled to the height.
+func scanReference(h int) *FNT {
+	for _, f := range []*FNT{Fnt8x8, Fnt8x14, Fnt8x16} {
+		if f.Height == h {
+			return f
+		}
+	}
+	return Fnt8x16.derive(Fnt8x16.Width, h, func(ch byte, x, y int) bool {
+		return Fnt8x16.Pixel(ch, x, y*Fnt8x16.Height/h)
+	})
+}
+
+// tableScore returns the score of the font table, see [Candidate].
+func tableScore(f, ref *FNT) float64 {
+	score := 0.8 * shapeScore(f, ref)
+	if !slices.ContainsFunc(f.Chars[0], func(b byte) bool { return b != 0 }) {
+		score += 0.1
+	}
+	if !slices.ContainsFunc(f.Chars[0xdb], func(b byte) bool { return b != 0xff }) {
+		score += 0.1
+	}
+	return score
+}
+
+// shapeScore returns the mean similarity of the scanShapes characters of the
+// font to the reference font.
+func shapeScore(f, ref *FNT) float64 {
+	var sum float64
+	for _, ch := range scanShapes {
+		sum += glyphSimilarity(f, ref, ch)
+	}
+	return sum / float64(len(scanShapes))
+}
+
+// glyphSimilarity returns the best Jaccard index of the set pixels of the
+// character in the fonts, with the small shifts allowed, as the fonts place
+// the characters in the cell differently.
+func glyphSimilarity(f, ref *FNT, ch byte) float64 {
+	var best float64
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -1; dx <= 1; dx++ {
+			var inter, union int
+			for y := range f.Height {
+				for x := range f.Width {
+					a, b := f.Pixel(ch, x, y), ref.Pixel(ch, x+dx, y+dy)
+					if a && b {
+						inter++
+					}
+					if a || b {
+						union++
+					}
+				}
+			}
+			if union > 0 {
+				best = max(best, float64(inter)/float64(union))
+			}
+		}
+	}
+	return best
+}
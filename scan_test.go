@@ -0,0 +1,80 @@
+package fontpic
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestScanFonts(t *testing.T) {
+	var (
+		rnd  = rand.New(rand.NewSource(1))
+		junk = func(n int) []byte {
+			b := make([]byte, n)
+			rnd.Read(b)
+			return b
+		}
+		data []byte
+	)
+	// the tables are placed between the random data and the zeroes, the
+	// 8x14 font is modified, so it does not match the reference exactly.
+	data = append(data, junk(1000)...)
+	data = append(data, Fnt8x14.Bold(1).Bytes()...)
+	data = append(data, make([]byte, 5000)...)
+	data = append(data, Fnt8x8.Bytes()...)
+	data = append(data, junk(777)...)
+	data = append(data, Fnt8x16.Bytes()...)
+	data = append(data, junk(100)...)
+
+	tests := []struct {
+		name string
+		opts ScanOptions
+		want []Candidate
+	}{
+		{
+			"default heights",
+			ScanOptions{},
+			[]Candidate{
+				{Offset: 1000, Height: 14},
+				{Offset: 9584, Height: 8},
+				{Offset: 12409, Height: 16},
+			},
+		},
+		{
+			"one height",
+			ScanOptions{Heights: []int{16}},
+			[]Candidate{
+				{Offset: 12409, Height: 16},
+			},
+		},
+		{
+			"no fonts",
+			ScanOptions{Heights: []int{12}},
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ScanFonts(data, tt.opts)
+			if len(got) != len(tt.want) {
+				t.Fatalf("ScanFonts() = %d candidates, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for i, c := range got {
+				if c.Offset != tt.want[i].Offset || c.Height != tt.want[i].Height {
+					t.Errorf("candidate %d = %d/%d, want %d/%d", i, c.Offset, c.Height, tt.want[i].Offset, tt.want[i].Height)
+				}
+				if c.Font == nil || c.Font.Height != c.Height {
+					t.Errorf("candidate %d font is invalid", i)
+				}
+			}
+		})
+	}
+}
+
+func Test_scanReference(t *testing.T) {
+	if ref := scanReference(16); ref != Fnt8x16 {
+		t.Error("reference for 16 is not Fnt8x16")
+	}
+	if ref := scanReference(12); ref.Width != 8 || ref.Height != 12 {
+		t.Errorf("reference for 12 is %dx%d", ref.Width, ref.Height)
+	}
+}
@@ -0,0 +1,80 @@
+package fontpic
+
+import (
+	"image"
+	"image/color"
+)
+
+// Span is a fragment of text rendered with the same font, style and colours.
+type Span struct {
+	Text  []byte
+	Style Style
+	// Font is the font of the span.  If nil, the canvas font is used.  Use
+	// [ImageFont.ToFnt] for the image fonts.
+	Font *FNT
+	// Foreground and Background are the colours of the span.  If nil, the
+	// canvas colours are used.
+	Foreground color.Color
+	Background color.Color
+}
+
+// RenderSpans renders the spans of text to the canvas.  Each span is rendered
+// with its font, or the canvas font, transformed with the span style.  The
+// fonts of different height are aligned on the common baseline, and the line
+// is as tall as the tallest font requires.  Same as with RenderText, newlines
+// separate the lines and tabs are replaced with spaces.  See [ParseMarkup]
+// for the spans from the marked up text.
+func (c *Canvas) RenderSpans(spans []Span) *Canvas {
+	return c.renderSpansAt(spans, image.Point{0, 0})
+}
+
+// RenderSpansAt renders the spans of text at the specified location.
+func (c *Canvas) RenderSpansAt(spans []Span, at image.Point) *Canvas {
+	return c.renderSpansAt(spans, at)
+}
+
+func (c *Canvas) renderSpansAt(spans []Span, at image.Point) *Canvas {
+	c.ensure()
+	return c.renderGlyphsAt(c.spanGlyphs(spans), at)
+}
+
+// spanGlyphs converts the spans to the lines of glyphs.
+func (c *Canvas) spanGlyphs(spans []Span) [][]glyph {
+	type styledKey struct {
+		font  *FNT
+		style Style
+	}
+	var (
+		styled = make(map[styledKey]*FNT)
+		lines  = [][]glyph{nil}
+	)
+	for _, sp := range spans {
+		key := styledKey{sp.Font, sp.Style}
+		if key.font == nil {
+			key.font = c.Font
+		}
+		fnt, ok := styled[key]
+		if !ok {
+			fnt = key.font.Styled(sp.Style)
+			styled[key] = fnt
+		}
+		g := glyph{font: fnt, fg: sp.Foreground, bg: sp.Background}
+		for _, ch := range sp.Text {
+			n := len(lines) - 1
+			switch ch {
+			case '\n':
+				lines = append(lines, nil)
+			case '\r':
+			case '\t':
+				g.ch = ' '
+				for range 8 {
+					lines[n] = append(lines[n], g)
+				}
+			default:
+				g.ch = ch
+				lines[n] = append(lines[n], g)
+			}
+		}
+	}
+	return lines
+}
@@ -0,0 +1,128 @@
+package fontpic
+
+// style.go contains the synthetic style transformations of the fonts.
+
+// Style is a set of the synthetic style flags.
+type Style uint8
+
+const (
+	StyleBold Style = 1 << iota
+	StyleItalic
+	StyleUnderline
+	StyleStrikeout
+	StyleOverline
+
+	StyleRegular Style = 0
+)
+
+// Bold returns the emboldened copy of the font.  Each pixel is smeared n
+// pixels to the right.  The pixels smeared past the right edge of the
+// character are lost.  Advances of the proportional fonts are increased by n.
+func (f *FNT) Bold(n int) *FNT {
+	nf := f.derive(f.Width, f.Height, func(ch byte, x, y int) bool {
+		for i := 0; i <= n; i++ {
+			if f.Pixel(ch, x-i, y) {
+				return true
+			}
+		}
+		return false
+	})
+	for i := range nf.Metrics {
+		nf.Metrics[i].Advance += n
+	}
+	return nf
+}
+
+// Oblique returns the slanted copy of the font.  Rows above the baseline are
+// shifted to the right by one pixel every step rows, and the rows below the
+// baseline are shifted to the left.  Step of 2 or 3 looks good with most of
+// the fonts.
+func (f *FNT) Oblique(step int) *FNT {
+	if step < 1 {
+		step = 1
+	}
+	baseline := f.Height - f.descent()
+	return f.derive(f.Width, f.Height, func(ch byte, x, y int) bool {
+		return f.Pixel(ch, x-floorDiv(baseline-1-y, step), y)
+	})
+}
+
+// floorDiv returns a/b rounded towards negative infinity.
+func floorDiv(a, b int) int {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q
+}
+
+// Line returns the copy of the font with a horizontal line drawn in every
+// character at the given row.
+func (f *FNT) Line(row int) *FNT {
+	return f.derive(f.Width, f.Height, func(ch byte, x, y int) bool {
+		return y == row || f.Pixel(ch, x, y)
+	})
+}
+
+// Underline returns the underlined copy of the font.  If row is negative, the
+// line is drawn at the first row below the baseline.
+func (f *FNT) Underline(row int) *FNT {
+	if row < 0 {
+		row = f.underlineRow()
+	}
+	return f.Line(row)
+}
+
+// Strikeout returns the struck out copy of the font.  If row is negative, the
+// line is drawn in the middle of the lowercase letters.
+func (f *FNT) Strikeout(row int) *FNT {
+	if row < 0 {
+		row = f.strikeoutRow()
+	}
+	return f.Line(row)
+}
+
+// Overline returns the overlined copy of the font.  If row is negative, the
+// line is drawn at the top row.
+func (f *FNT) Overline(row int) *FNT {
+	if row < 0 {
+		row = 0
+	}
+	return f.Line(row)
+}
+
+func (f *FNT) underlineRow() int {
+	return f.Height - f.descent()
+}
+
+func (f *FNT) strikeoutRow() int {
+	baseline := f.Height - f.descent()
+	return baseline - baseline/3
+}
+
+// Styled returns the copy of the font with the style s applied, using the
+// default parameters: 1 pixel bold smear, oblique slant of 1 pixel every 3
+// rows, and the default line positions.  For the regular style it returns the
+// font itself.
+func (f *FNT) Styled(s Style) *FNT {
+	if s == StyleRegular {
+		return f
+	}
+	nf := f
+	if s&StyleBold != 0 {
+		nf = nf.Bold(1)
+	}
+	if s&StyleItalic != 0 {
+		nf = nf.Oblique(3)
+	}
+	if s&StyleUnderline != 0 {
+		nf = nf.Underline(-1)
+	}
+	if s&StyleStrikeout != 0 {
+		nf = nf.Strikeout(-1)
+	}
+	if s&StyleOverline != 0 {
+		nf = nf.Overline(-1)
+	}
+	return nf
+}
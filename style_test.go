@@ -0,0 +1,156 @@
+package fontpic
+
+import (
+	"image/color"
+	"strings"
+	"testing"
+)
+
+// glyphString returns the character bitmap as a string of '#' and '.'
+// characters, one line per row.
+func glyphString(f *FNT, ch byte) string {
+	var sb strings.Builder
+	for y := range f.Height {
+		for x := range f.Width {
+			if f.Pixel(ch, x, y) {
+				sb.WriteByte('#')
+			} else {
+				sb.WriteByte('.')
+			}
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// testFont returns a 4x4 font with the character 'I' being a vertical bar in
+// the second column.
+func testFont() *FNT {
+	f := &FNT{Width: 4, Height: 4}
+	f.Chars = toChars(make([]byte, CharsetSz*4), 4, 4)
+	for y := range 4 {
+		f.SetPixel('I', 1, y, true)
+	}
+	return f
+}
+
+func TestFNT_styles(t *testing.T) {
+	tests := []struct {
+		name string
+		fnt  *FNT
+		want string
+	}{
+		{
+			"regular",
+			testFont(),
+			".#..\n.#..\n.#..\n.#..\n",
+		},
+		{
+			"bold",
+			testFont().Bold(1),
+			".##.\n.##.\n.##.\n.##.\n",
+		},
+		{
+			"bold 3",
+			testFont().Bold(3),
+			".###\n.###\n.###\n.###\n",
+		},
+		{
+			"oblique",
+			testFont().Oblique(1),
+			"...#\n..#.\n.#..\n#...\n",
+		},
+		{
+			"underline",
+			testFont().Underline(-1),
+			".#..\n.#..\n.#..\n####\n",
+		},
+		{
+			"strikeout",
+			testFont().Strikeout(1),
+			".#..\n####\n.#..\n.#..\n",
+		},
+		{
+			"overline",
+			testFont().Overline(-1),
+			"####\n.#..\n.#..\n.#..\n",
+		},
+		{
+			"bold underline",
+			testFont().Styled(StyleBold | StyleUnderline),
+			".##.\n.##.\n.##.\n####\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := glyphString(tt.fnt, 'I'); got != tt.want {
+				t.Errorf("glyph:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFNT_Styled_regular(t *testing.T) {
+	if got := Fnt8x16.Styled(StyleRegular); got != Fnt8x16 {
+		t.Error("Styled(StyleRegular) returned a copy")
+	}
+	if got := Fnt8x16.Styled(StyleBold); got.Charset != Fnt8x16.Charset {
+		t.Errorf("Charset = %q, want %q", got.Charset, Fnt8x16.Charset)
+	}
+}
+
+func TestCanvas_RenderSpans(t *testing.T) {
+	fnt := testFont().Proportional(2, 1)
+	c := NewCanvas(fnt).RenderSpans([]Span{
+		{Text: []byte("I")},
+		{Text: []byte("I"), Style: StyleBold},
+		{Text: []byte("\nI")},
+	})
+	if c.Width != 2+3 {
+		t.Errorf("Width = %d, want 5", c.Width)
+	}
+	if c.Height != 8 {
+		t.Errorf("Height = %d, want 8", c.Height)
+	}
+}
+
+func TestCanvas_RenderSpans_fonts(t *testing.T) {
+	var (
+		big = &FNT{Width: 6, Height: 10}
+		red = color.RGBA{0xff, 0, 0, 0xff}
+		blu = color.RGBA{0, 0, 0xff, 0xff}
+	)
+	big.Chars = toChars(make([]byte, CharsetSz*10), 6, 10)
+	c := NewCanvas(big).WithForeground(color.White).RenderSpans([]Span{
+		{Text: []byte("I")},
+		{Text: []byte("I"), Font: testFont(), Foreground: red, Background: blu},
+		{Text: []byte("\nI"), Font: testFont()},
+	})
+	// the first line is as tall as the big font, the second one as the small.
+	if c.Width != 6+4 || c.Height != 10+4 {
+		t.Fatalf("size = %dx%d, want 10x14", c.Width, c.Height)
+	}
+	img := c.Image()
+	tests := []struct {
+		name string
+		x, y int
+		want color.Color
+	}{
+		// the baseline of the big font is at row 8, the small glyph is
+		// aligned to it: its rows 0-3 are drawn at rows 5-8.
+		{"span above the glyph", 7, 4, blu},
+		{"span glyph top", 7, 5, red},
+		{"span glyph bottom", 7, 8, red},
+		{"span below the glyph", 7, 9, blu},
+		{"span background", 9, 0, blu},
+		{"canvas background", 0, 0, color.Black},
+		{"second line", 1, 10, color.White},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !colEq(img.At(tt.x, tt.y), tt.want) {
+				t.Errorf("At(%d, %d) = %v, want %v", tt.x, tt.y, img.At(tt.x, tt.y), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package fontpic
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"slices"
+)
+
+// svg.go contains the SVG backend for the rendered text and the glyph sheets.
+// The pixels of each glyph row are merged into the horizontal runs, so that
+// the output stays small and scales without blurring.
+
+// SVGOptions are the options of the SVG output.
+type SVGOptions struct {
+	// Symbols enables the reuse of glyphs: each distinct glyph is defined
+	// once as a <symbol> and placed with <use>, which keeps the files with
+	// a lot of text small.
+	Symbols bool
+	// Scale is the integer scale of the document size of the font sample,
+	// the view box stays in pixels.  If zero, 1 is used.  The text of the
+	// canvas is scaled with the canvas Scale.
+	Scale int
+}
+
+// WriteSVG writes the text to w as SVG, the same way as RenderText renders
+// it to the image.  The document size is the canvas size multiplied by the
+// Scale, the view box is the canvas size in pixels.  Effects and the image
+// set with WithImage are not included in the output.
+func (c *Canvas) WriteSVG(w io.Writer, text []byte, opts SVGOptions) error {
+	c.ensure()
+	return c.writeSVG(w, c.toGlyphs(textLines(text)), opts)
+}
+
+// WriteSpansSVG writes the spans of text to w as SVG, the same way as
+// RenderSpans renders them to the image.
+func (c *Canvas) WriteSpansSVG(w io.Writer, spans []Span, opts SVGOptions) error {
+	c.ensure()
+	return c.writeSVG(w, c.spanGlyphs(spans), opts)
+}
+
+func (c *Canvas) writeSVG(w io.Writer, lines [][]glyph, opts SVGOptions) error {
+	if c.Width == 0 || c.Height == 0 {
+		c.calcSize(lines)
+	}
+	glyphs, fills := c.layout(lines, c.origin)
+	// the canvas background is the single rectangle of the document.
+	fills = slices.DeleteFunc(fills, func(f fillRect) bool { return f.color == nil })
+	sw := svgWriter{
+		fills: fills,
+		size:  image.Pt(c.Width, c.Height),
+		scale: c.Scale,
+		fg:    c.Foreground,
+		bg:    c.Background,
+		opts:  opts,
+	}
+	return sw.write(w, glyphs)
+}
+
+// WriteSampleSVG writes the font sample to w as SVG.  The layout and the
+// colours are the same as of the Sample image.
+func (f *FNT) WriteSampleSVG(w io.Writer, perLine int, opts SVGOptions) error {
+	return f.writeSampleSVG(w, perLine, color.Gray{0xa8}, color.Black, image.Point{1, 1}, opts)
+}
+
+// WriteSampleColorSVG writes the font sample with the fg and bg colours to w
+// as SVG.
+func (f *FNT) WriteSampleColorSVG(w io.Writer, perLine int, fg, bg color.Color, opts SVGOptions) error {
+	return f.writeSampleSVG(w, perLine, fg, bg, image.Point{1, 1}, opts)
+}
+
+func (f *FNT) writeSampleSVG(w io.Writer, perLine int, fg, bg color.Color, spacing image.Point, opts SVGOptions) error {
+	// the sample shows the whole character cells, same as the image.
+	mono := *f
+	mono.Metrics = nil
+	var (
+		cell   = image.Pt(f.Width, f.Height)
+		glyphs = make([]placedGlyph, 0, CharsetSz)
+	)
+	for i := range CharsetSz {
+		glyphs = append(glyphs, placedGlyph{
+			glyph: glyph{ch: byte(i), font: &mono},
+			at:    sampleAt(perLine, i, cell, spacing),
+		})
+	}
+	scale := max(opts.Scale, 1)
+	sw := svgWriter{
+		size:  sampleSize(perLine, cell, spacing),
+		scale: image.Point{scale, scale},
+		fg:    fg,
+		bg:    bg,
+		opts:  opts,
+	}
+	return sw.write(w, glyphs)
+}
+
+// svgWriter writes the positioned glyphs as the SVG document.
+type svgWriter struct {
+	size   image.Point // size of the view box in pixels.
+	scale  image.Point
+	fg, bg color.Color
+	fills  []fillRect // backgrounds of the spans.
+	opts   SVGOptions
+}
+
+// symbolKey identifies the glyph of the font.
+type symbolKey struct {
+	font *FNT
+	ch   byte
+}
+
+func (sw svgWriter) write(w io.Writer, glyphs []placedGlyph) error {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`+"\n",
+		sw.size.X*sw.scale.X, sw.size.Y*sw.scale.Y, sw.size.X, sw.size.Y)
+	if _, _, _, a := sw.bg.RGBA(); a != 0 {
+		fmt.Fprintf(&buf, `<rect width="%d" height="%d"%s/>`+"\n", sw.size.X, sw.size.Y, svgFill(sw.bg))
+	}
+	for _, f := range sw.fills {
+		fmt.Fprintf(&buf, `<rect x="%d" y="%d" width="%d" height="%d"%s/>`+"\n", f.Min.X, f.Min.Y, f.Dx(), f.Dy(), svgFill(f.color))
+	}
+	if sw.opts.Symbols {
+		sw.writeSymbols(&buf, glyphs)
+	} else {
+		fmt.Fprintf(&buf, "<g%s>\n", svgFill(sw.fg))
+		for _, g := range glyphs {
+			if d := glyphPath(g.font, g.ch, g.at); d != "" {
+				fmt.Fprintf(&buf, `<path d="%s"%s/>`+"\n", d, glyphFill(g.glyph))
+			}
+		}
+		buf.WriteString("</g>\n")
+	}
+	buf.WriteString("</svg>\n")
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// writeSymbols writes each distinct glyph once as a symbol, and then the
+// references to the symbols.  Blank glyphs are omitted.
+func (sw svgWriter) writeSymbols(buf *bytes.Buffer, glyphs []placedGlyph) {
+	var (
+		ids   = make(map[symbolKey]string)
+		fonts = make(map[*FNT]int)
+	)
+	buf.WriteString("<defs>\n")
+	for _, g := range glyphs {
+		key := symbolKey{g.font, g.ch}
+		if _, seen := ids[key]; seen {
+			continue
+		}
+		d := glyphPath(g.font, g.ch, image.Point{})
+		if d == "" {
+			ids[key] = ""
+			continue
+		}
+		fi, ok := fonts[g.font]
+		if !ok {
+			fi = len(fonts)
+			fonts[g.font] = fi
+		}
+		id := fmt.Sprintf("f%dc%02x", fi, g.ch)
+		ids[key] = id
+		fmt.Fprintf(buf, `<symbol id="%s" overflow="visible"><path d="%s"/></symbol>`+"\n", id, d)
+	}
+	buf.WriteString("</defs>\n")
+	fmt.Fprintf(buf, "<g%s>\n", svgFill(sw.fg))
+	for _, g := range glyphs {
+		if id := ids[symbolKey{g.font, g.ch}]; id != "" {
+			fmt.Fprintf(buf, `<use href="#%s" x="%d" y="%d"%s/>`+"\n", id, g.at.X, g.at.Y, glyphFill(g.glyph))
+		}
+	}
+	buf.WriteString("</g>\n")
+}
+
+// glyphPath returns the path data of the glyph at the given point, where
+// each run of the set pixels in a row is a rectangle.  It returns an empty
+// string for the blank glyph.
+func glyphPath(f *FNT, ch byte, at image.Point) string {
+	var (
+		buf       bytes.Buffer
+		left, adv = f.hmetrics(ch)
+	)
+	for y := range f.Height {
+		for x := 0; x < adv; x++ {
+			if !f.Pixel(ch, left+x, y) {
+				continue
+			}
+			start := x
+			for x < adv && f.Pixel(ch, left+x, y) {
+				x++
+			}
+			fmt.Fprintf(&buf, "M%d %dh%dv1h%dz", at.X+start, at.Y+y, x-start, start-x)
+		}
+	}
+	return buf.String()
+}
+
+// glyphFill returns the fill attributes of the glyph with its own colour, or
+// an empty string, if the glyph is drawn in the canvas colour.
+func glyphFill(g glyph) string {
+	if g.fg == nil {
+		return ""
+	}
+	return svgFill(g.fg)
+}
+
+// svgFill returns the fill attributes for the color.
+func svgFill(c color.Color) string {
+	nc := color.NRGBAModel.Convert(c).(color.NRGBA)
+	if nc.A == 0 {
+		return ` fill="none"`
+	}
+	fill := fmt.Sprintf(` fill="#%02x%02x%02x"`, nc.R, nc.G, nc.B)
+	if nc.A != 0xff {
+		fill += fmt.Sprintf(` fill-opacity="%.3g"`, float64(nc.A)/0xff)
+	}
+	return fill
+}
@@ -0,0 +1,243 @@
+package fontpic
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"image/color"
+	"slices"
+	"testing"
+)
+
+func Test_glyphPath(t *testing.T) {
+	tests := []struct {
+		name string
+		fnt  *FNT
+		ch   byte
+		at   image.Point
+		want string
+	}{
+		{
+			"bar",
+			testFont(),
+			'I',
+			image.Point{},
+			"M1 0h1v1h-1zM1 1h1v1h-1zM1 2h1v1h-1zM1 3h1v1h-1z",
+		},
+		{
+			"runs are merged",
+			testFont().Bold(1),
+			'I',
+			image.Point{10, 20},
+			"M11 20h2v1h-2zM11 21h2v1h-2zM11 22h2v1h-2zM11 23h2v1h-2z",
+		},
+		{
+			"proportional",
+			testFont().Proportional(2, 1),
+			'I',
+			image.Point{},
+			"M0 0h1v1h-1zM0 1h1v1h-1zM0 2h1v1h-1zM0 3h1v1h-1z",
+		},
+		{
+			"blank",
+			testFont(),
+			' ',
+			image.Point{},
+			"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := glyphPath(tt.fnt, tt.ch, tt.at); got != tt.want {
+				t.Errorf("glyphPath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// svgDoc is the subset of the SVG document produced by the writer.
+type svgDoc struct {
+	Width   int    `xml:"width,attr"`
+	Height  int    `xml:"height,attr"`
+	ViewBox string `xml:"viewBox,attr"`
+	Shape   string `xml:"shape-rendering,attr"`
+	Rects   []struct {
+		Fill string `xml:"fill,attr"`
+	} `xml:"rect"`
+	Symbols []struct {
+		ID string `xml:"id,attr"`
+	} `xml:"defs>symbol"`
+	G struct {
+		Fill  string `xml:"fill,attr"`
+		Paths []struct {
+			D    string `xml:"d,attr"`
+			Fill string `xml:"fill,attr"`
+		} `xml:"path"`
+		Uses []struct {
+			Href string `xml:"href,attr"`
+			X    int    `xml:"x,attr"`
+			Fill string `xml:"fill,attr"`
+		} `xml:"use"`
+	} `xml:"g"`
+}
+
+func TestCanvas_WriteSVG(t *testing.T) {
+	tests := []struct {
+		name        string
+		canvas      *Canvas
+		text        string
+		opts        SVGOptions
+		wantWidth   int
+		wantViewBox string
+		wantPaths   int
+		wantSymbols int
+		wantUses    []int
+	}{
+		{
+			"paths",
+			NewCanvas(testFont()),
+			"II I",
+			SVGOptions{},
+			16,
+			"0 0 16 4",
+			3,
+			0,
+			nil,
+		},
+		{
+			"symbols",
+			NewCanvas(testFont()),
+			"II I",
+			SVGOptions{Symbols: true},
+			16,
+			"0 0 16 4",
+			0,
+			1,
+			[]int{0, 4, 12},
+		},
+		{
+			"scaled",
+			&Canvas{Font: testFont(), Scale: image.Point{3, 3}},
+			"I\nI",
+			SVGOptions{},
+			12,
+			"0 0 4 8",
+			2,
+			0,
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tt.canvas.WriteSVG(&buf, []byte(tt.text), tt.opts); err != nil {
+				t.Fatalf("WriteSVG() error = %v", err)
+			}
+			var doc svgDoc
+			if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+				t.Fatalf("invalid SVG: %v\n%s", err, buf.String())
+			}
+			if doc.Width != tt.wantWidth {
+				t.Errorf("width = %d, want %d", doc.Width, tt.wantWidth)
+			}
+			if doc.ViewBox != tt.wantViewBox {
+				t.Errorf("viewBox = %q, want %q", doc.ViewBox, tt.wantViewBox)
+			}
+			if doc.Shape != "crispEdges" {
+				t.Errorf("shape-rendering = %q", doc.Shape)
+			}
+			if len(doc.Rects) != 1 || doc.Rects[0].Fill != "#000000" {
+				t.Errorf("background = %+v, want one black rect", doc.Rects)
+			}
+			if doc.G.Fill != "#a8a8a8" {
+				t.Errorf("fill = %q, want #a8a8a8", doc.G.Fill)
+			}
+			if len(doc.G.Paths) != tt.wantPaths {
+				t.Errorf("paths = %d, want %d", len(doc.G.Paths), tt.wantPaths)
+			}
+			if len(doc.Symbols) != tt.wantSymbols {
+				t.Errorf("symbols = %d, want %d", len(doc.Symbols), tt.wantSymbols)
+			}
+			var uses []int
+			for _, u := range doc.G.Uses {
+				if u.Href != "#"+doc.Symbols[0].ID {
+					t.Errorf("use href = %q", u.Href)
+				}
+				uses = append(uses, u.X)
+			}
+			if !slices.Equal(uses, tt.wantUses) {
+				t.Errorf("uses at %v, want %v", uses, tt.wantUses)
+			}
+		})
+	}
+}
+
+func TestCanvas_WriteSpansSVG(t *testing.T) {
+	spans := []Span{
+		{Text: []byte("I")},
+		{Text: []byte("I"), Foreground: color.RGBA{0xff, 0, 0, 0xff}, Background: color.RGBA{0, 0, 0xff, 0xff}},
+	}
+	for _, symbols := range []bool{false, true} {
+		var buf bytes.Buffer
+		if err := NewCanvas(testFont()).WriteSpansSVG(&buf, spans, SVGOptions{Symbols: symbols}); err != nil {
+			t.Fatalf("WriteSpansSVG() error = %v", err)
+		}
+		var doc svgDoc
+		if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+			t.Fatalf("invalid SVG: %v", err)
+		}
+		// the canvas background and the background of the span.
+		if len(doc.Rects) != 2 || doc.Rects[1].Fill != "#0000ff" {
+			t.Errorf("symbols=%v: rects = %+v, want the canvas and #0000ff", symbols, doc.Rects)
+		}
+		var fills []string
+		for _, p := range doc.G.Paths {
+			fills = append(fills, p.Fill)
+		}
+		for _, u := range doc.G.Uses {
+			fills = append(fills, u.Fill)
+		}
+		if want := []string{"", "#ff0000"}; !slices.Equal(fills, want) {
+			t.Errorf("symbols=%v: glyph fills = %q, want %q", symbols, fills, want)
+		}
+	}
+}
+
+func TestFNT_WriteSampleSVG(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testFont().WriteSampleColorSVG(&buf, 16, color.White, color.Transparent, SVGOptions{Symbols: true}); err != nil {
+		t.Fatalf("WriteSampleColorSVG() error = %v", err)
+	}
+	var doc svgDoc
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid SVG: %v", err)
+	}
+	// same size as the Sample image.
+	img := testFont().Sample(16)
+	if doc.Width != img.Bounds().Dx() || doc.Height != img.Bounds().Dy() {
+		t.Errorf("size = %dx%d, want %v", doc.Width, doc.Height, img.Bounds().Size())
+	}
+	if len(doc.Rects) != 0 {
+		t.Errorf("transparent background is drawn")
+	}
+	if doc.G.Fill != "#ffffff" {
+		t.Errorf("fill = %q, want #ffffff", doc.G.Fill)
+	}
+	// only 'I' (0x49) is not blank, it is in the column 9.
+	if len(doc.G.Uses) != 1 || doc.G.Uses[0].X != 9*5 {
+		t.Errorf("uses = %+v, want one at x=45", doc.G.Uses)
+	}
+
+	// the scale enlarges the document, not the view box.
+	buf.Reset()
+	if err := testFont().WriteSampleSVG(&buf, 16, SVGOptions{Scale: 2}); err != nil {
+		t.Fatalf("WriteSampleSVG() error = %v", err)
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid SVG: %v", err)
+	}
+	if doc.Width != 2*img.Bounds().Dx() || doc.ViewBox != fmt.Sprintf("0 0 %d %d", img.Bounds().Dx(), img.Bounds().Dy()) {
+		t.Errorf("scaled width = %d, viewBox = %q", doc.Width, doc.ViewBox)
+	}
+}
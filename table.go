@@ -0,0 +1,212 @@
+package fontpic
+
+import (
+	"bytes"
+	"image"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rusq/fontpic/charset"
+)
+
+// table.go contains the layout of the text tables with the box-drawing
+// borders.
+
+// Align is the alignment of the text in the table column.
+type Align uint8
+
+const (
+	AlignLeft Align = iota
+	AlignRight
+	AlignCenter
+)
+
+// TableBorder are the characters of the table borders.  Top, Header and
+// Bottom are the horizontal lines of the table: the left end, the line, the
+// junction with the column separator, and the right end.
+type TableBorder struct {
+	Top    [4]rune
+	Header [4]rune // separator between the header and the rows.
+	Bottom [4]rune
+	// Vertical is the left and the right line, and the column separator.
+	Vertical rune
+}
+
+var (
+	// TableSingle is the single line frame with the double header separator.
+	TableSingle = TableBorder{
+		Top:      [4]rune{'┌', '─', '┬', '┐'},
+		Header:   [4]rune{'╞', '═', '╪', '╡'},
+		Bottom:   [4]rune{'└', '─', '┴', '┘'},
+		Vertical: '│',
+	}
+	// TableDouble is the double line frame with the single header separator.
+	TableDouble = TableBorder{
+		Top:      [4]rune{'╔', '═', '╦', '╗'},
+		Header:   [4]rune{'╟', '─', '╫', '╢'},
+		Bottom:   [4]rune{'╚', '═', '╩', '╝'},
+		Vertical: '║',
+	}
+	// TableASCII is the frame of the ASCII characters, it is used for the
+	// character sets without the box-drawing characters.
+	TableASCII = TableBorder{
+		Top:      [4]rune{'+', '-', '+', '+'},
+		Header:   [4]rune{'+', '=', '+', '+'},
+		Bottom:   [4]rune{'+', '-', '+', '+'},
+		Vertical: '|',
+	}
+)
+
+// runes returns all the characters of the border.
+func (b TableBorder) runes() []rune {
+	ret := make([]rune, 0, 13)
+	ret = append(ret, b.Top[:]...)
+	ret = append(ret, b.Header[:]...)
+	ret = append(ret, b.Bottom[:]...)
+	return append(ret, b.Vertical)
+}
+
+// Table is the table of text, laid out in the columns with the borders.  The
+// cells are single lines of text, one space of padding is added on each side
+// of the cell.
+type Table struct {
+	// Header is the optional header row, it is separated from the rows with
+	// the header line of the border.
+	Header []string
+	Rows   [][]string
+	// Align is the alignment of the columns.  The columns without the
+	// alignment are aligned to the left.
+	Align []Align
+	// Border is the border of the table.  If zero, TableSingle is used.
+	Border TableBorder
+}
+
+// widths returns the widths of the columns in characters.
+func (t *Table) widths() []int {
+	var widths []int
+	for _, row := range append([][]string{t.Header}, t.Rows...) {
+		for i, cell := range row {
+			if i == len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+	return widths
+}
+
+// Lines returns the lines of the table.  It returns nil for the table without
+// the columns.
+func (t *Table) Lines() []string {
+	return t.lines(t.Border)
+}
+
+func (t *Table) lines(border TableBorder) []string {
+	if border == (TableBorder{}) {
+		border = TableSingle
+	}
+	widths := t.widths()
+	if len(widths) == 0 {
+		return nil
+	}
+	rule := func(r [4]rune) string {
+		var sb strings.Builder
+		sb.WriteRune(r[0])
+		for i, w := range widths {
+			if i > 0 {
+				sb.WriteRune(r[2])
+			}
+			sb.WriteString(strings.Repeat(string(r[1]), w+2))
+		}
+		sb.WriteRune(r[3])
+		return sb.String()
+	}
+	row := func(cells []string) string {
+		var sb strings.Builder
+		sb.WriteRune(border.Vertical)
+		for i, w := range widths {
+			var cell string
+			if i < len(cells) {
+				cell = cells[i]
+			}
+			var align Align
+			if i < len(t.Align) {
+				align = t.Align[i]
+			}
+			sb.WriteByte(' ')
+			sb.WriteString(pad(cell, w, align))
+			sb.WriteByte(' ')
+			sb.WriteRune(border.Vertical)
+		}
+		return sb.String()
+	}
+
+	ret := []string{rule(border.Top)}
+	if len(t.Header) > 0 {
+		ret = append(ret, row(t.Header), rule(border.Header))
+	}
+	for _, cells := range t.Rows {
+		ret = append(ret, row(cells))
+	}
+	return append(ret, rule(border.Bottom))
+}
+
+// pad pads the string s with spaces to the width w.
+func pad(s string, w int, align Align) string {
+	n := w - utf8.RuneCountInString(s)
+	if n <= 0 {
+		return s
+	}
+	switch align {
+	case AlignRight:
+		return strings.Repeat(" ", n) + s
+	case AlignCenter:
+		return strings.Repeat(" ", n/2) + s + strings.Repeat(" ", n-n/2)
+	default:
+		return s + strings.Repeat(" ", n)
+	}
+}
+
+// Text returns the table in the character set cs, the lines are separated
+// with newlines.  If the character set lacks any of the border characters,
+// the border is replaced with TableASCII.  The box-drawing characters of
+// CP866 are at the same codes as in CP437, so the text is suitable for both.
+func (t *Table) Text(cs charset.Charset) []byte {
+	border := t.Border
+	if border == (TableBorder{}) {
+		border = TableSingle
+	}
+	for _, r := range border.runes() {
+		if _, ok := cs.Lookup(r); !ok {
+			border = TableASCII
+			break
+		}
+	}
+	return cs.Translate(strings.Join(t.lines(border), "\n"))
+}
+
+// RenderTable renders the table with the canvas font, using the font
+// character set, see [Table.Text].  The characters are placed on the grid of
+// the font cell, ignoring the advances of the proportional fonts and the
+// kerning, so that the borders are continuous and the columns are aligned.
+// For the same reason, the spacing of the canvas should be zero.
+func (c *Canvas) RenderTable(t *Table) *Canvas {
+	return c.RenderTableAt(t, image.Point{0, 0})
+}
+
+// RenderTableAt renders the table at the specified location.
+func (c *Canvas) RenderTableAt(t *Table, at image.Point) *Canvas {
+	c.ensure()
+	cs, _ := charset.ByName(c.Font.Charset)
+	mono := *c.Font
+	mono.Metrics, mono.Kerning = nil, nil
+	var lines [][]glyph
+	for _, line := range bytes.Split(t.Text(cs), []byte("\n")) {
+		gl := make([]glyph, len(line))
+		for i, ch := range line {
+			gl[i] = glyph{ch: ch, font: &mono}
+		}
+		lines = append(lines, gl)
+	}
+	return c.renderGlyphsAt(lines, at)
+}
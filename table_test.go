@@ -0,0 +1,116 @@
+package fontpic
+
+import (
+	"image"
+	"strings"
+	"testing"
+
+	"github.com/rusq/fontpic/charset"
+)
+
+func TestTable_Lines(t *testing.T) {
+	tests := []struct {
+		name  string
+		table Table
+		want  string
+	}{
+		{
+			"single with header",
+			Table{
+				Header: []string{"Name", "Qty"},
+				Rows:   [][]string{{"Apple", "3"}, {"Fig", "12"}},
+				Align:  []Align{AlignLeft, AlignRight},
+			},
+			"┌───────┬─────┐\n" +
+				"│ Name  │ Qty │\n" +
+				"╞═══════╪═════╡\n" +
+				"│ Apple │   3 │\n" +
+				"│ Fig   │  12 │\n" +
+				"└───────┴─────┘",
+		},
+		{
+			"double without header",
+			Table{
+				Rows:   [][]string{{"a", "b", "c"}, {"dd"}},
+				Align:  []Align{AlignCenter},
+				Border: TableDouble,
+			},
+			"╔════╦═══╦═══╗\n" +
+				"║ a  ║ b ║ c ║\n" +
+				"║ dd ║   ║   ║\n" +
+				"╚════╩═══╩═══╝",
+		},
+		{
+			"ascii",
+			Table{Header: []string{"x"}, Rows: [][]string{{"yyy"}}, Align: []Align{AlignCenter}, Border: TableASCII},
+			"+-----+\n" +
+				"|  x  |\n" +
+				"+=====+\n" +
+				"| yyy |\n" +
+				"+-----+",
+		},
+		{"empty", Table{}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := strings.Join(tt.table.Lines(), "\n"); got != tt.want {
+				t.Errorf("Lines() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTable_Text(t *testing.T) {
+	table := Table{Header: []string{"Ключ"}, Rows: [][]string{{"v"}}}
+	tests := []struct {
+		name string
+		cs   charset.Charset
+		want []byte
+	}{
+		{
+			"cp866",
+			charset.CP866,
+			[]byte("\xda\xc4\xc4\xc4\xc4\xc4\xc4\xbf\n\xb3 \x8a\xab\xee\xe7 \xb3\n\xc6"),
+		},
+		{
+			"ascii fallback",
+			"",
+			[]byte("+------+\n"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := table.Text(tt.cs)
+			if !strings.HasPrefix(string(got), string(tt.want)) {
+				t.Errorf("Text() = %q, want prefix %q", got, tt.want)
+			}
+			if lines := strings.Split(string(got), "\n"); len(lines) != 5 {
+				t.Errorf("lines = %d, want 5", len(lines))
+			}
+		})
+	}
+}
+
+func TestCanvas_RenderTable(t *testing.T) {
+	table := &Table{Header: []string{"A"}, Rows: [][]string{{"BC"}}}
+	// the proportional font is rendered on the grid of the cell.
+	fnt := Fnt8x16.Proportional(1, 1)
+	c := NewCanvas(fnt).RenderTable(table)
+	if want := image.Pt(6*8, 5*16); c.Image().Bounds().Size() != want {
+		t.Errorf("size = %v, want %v", c.Image().Bounds().Size(), want)
+	}
+	// the top border is continuous, from the middle of the corner to the
+	// middle of the other corner.
+	line := -1
+	for y := range fnt.Height {
+		if fnt.Pixel(0xc4, 0, y) {
+			line = y
+			break
+		}
+	}
+	for x := 4; x < 6*8-4; x++ {
+		if !colEq(c.Image().At(x, line), c.Foreground) {
+			t.Fatalf("top border has a gap at %d", x)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package fontpic
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"io"
+	"strings"
+)
+
+// term.go contains the encoders that print the images to the terminal.
+
+// TermMode is the way the image is printed to the terminal.
+type TermMode int
+
+const (
+	// TermHalfBlock prints two pixels per character cell with the upper
+	// half block and the 24-bit foreground and background colours.
+	TermHalfBlock TermMode = iota
+	// TermBraille prints 2x4 pixels per character cell with the braille
+	// dots.  It is monochrome: the dot is set where the pixel differs from
+	// the background, which is the colour of the top-left pixel.
+	TermBraille
+	// TermSixel prints the image with the DEC sixel graphics.
+	TermSixel
+)
+
+func (m TermMode) String() string {
+	switch m {
+	case TermHalfBlock:
+		return "halfblock"
+	case TermBraille:
+		return "braille"
+	case TermSixel:
+		return "sixel"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseTermMode returns the terminal mode by its name, as returned by
+// [TermMode.String].
+func ParseTermMode(s string) (TermMode, error) {
+	for m := TermHalfBlock; m <= TermSixel; m++ {
+		if strings.EqualFold(s, m.String()) {
+			return m, nil
+		}
+	}
+	return 0, errors.New("unknown terminal mode: " + s)
+}
+
+// WriteTerm writes the image to w, in the form that is displayed by the
+// terminal.
+func WriteTerm(w io.Writer, img image.Image, mode TermMode) error {
+	bw := bufio.NewWriter(w)
+	switch mode {
+	case TermHalfBlock:
+		writeHalfBlock(bw, img)
+	case TermBraille:
+		writeBraille(bw, img)
+	case TermSixel:
+		writeSixel(bw, img)
+	default:
+		return errors.New("unknown terminal mode")
+	}
+	return bw.Flush()
+}
+
+func rgbaAt(img image.Image, x, y int) color.RGBA {
+	return color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
+}
+
+// writeHalfBlock writes the image with the upper half blocks, where the
+// foreground colour is the upper pixel and the background is the lower one.
+// The colours are set only when they change.
+func writeHalfBlock(w *bufio.Writer, img image.Image) {
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y += 2 {
+		var fg, bg *color.RGBA
+		for x := b.Min.X; x < b.Max.X; x++ {
+			top := rgbaAt(img, x, y)
+			if fg == nil || *fg != top {
+				fmt.Fprintf(w, "\x1b[38;2;%d;%d;%dm", top.R, top.G, top.B)
+				fg = &top
+			}
+			if y+1 < b.Max.Y {
+				bottom := rgbaAt(img, x, y+1)
+				if bg == nil || *bg != bottom {
+					fmt.Fprintf(w, "\x1b[48;2;%d;%d;%dm", bottom.R, bottom.G, bottom.B)
+					bg = &bottom
+				}
+			}
+			w.WriteRune('▀')
+		}
+		w.WriteString("\x1b[0m\n")
+	}
+}
+
+// brailleDots are the bits of the braille dots, indexed by the pixel
+// position in the 2x4 cell.
+var brailleDots = [4][2]rune{
+	{0x01, 0x08},
+	{0x02, 0x10},
+	{0x04, 0x20},
+	{0x40, 0x80},
+}
+
+// writeBraille writes the image with the braille characters.
+func writeBraille(w *bufio.Writer, img image.Image) {
+	b := img.Bounds()
+	if b.Empty() {
+		return
+	}
+	bg := rgbaAt(img, b.Min.X, b.Min.Y)
+	for y := b.Min.Y; y < b.Max.Y; y += 4 {
+		for x := b.Min.X; x < b.Max.X; x += 2 {
+			var r rune = 0x2800
+			for dy := range 4 {
+				for dx := range 2 {
+					pt := image.Pt(x+dx, y+dy)
+					if pt.In(b) && rgbaAt(img, pt.X, pt.Y) != bg {
+						r |= brailleDots[dy][dx]
+					}
+				}
+			}
+			w.WriteRune(r)
+		}
+		w.WriteByte('\n')
+	}
+}
+
+// writeSixel writes the image as the sixel graphics.  Images with more than
+// 256 colours are reduced to the web-safe palette.
+func writeSixel(w *bufio.Writer, img image.Image) {
+	var (
+		b    = img.Bounds()
+		pal  = imagePalette(img)
+		idx  = make([]uint8, b.Dx()*b.Dy())
+		seen = make(map[color.RGBA]uint8)
+	)
+	for y := range b.Dy() {
+		for x := range b.Dx() {
+			c := rgbaAt(img, b.Min.X+x, b.Min.Y+y)
+			i, ok := seen[c]
+			if !ok {
+				i = uint8(pal.Index(c))
+				seen[c] = i
+			}
+			idx[y*b.Dx()+x] = i
+		}
+	}
+	// DCS, aspect ratio 1:1, raster attributes and the palette in percent.
+	fmt.Fprintf(w, "\x1bPq\"1;1;%d;%d", b.Dx(), b.Dy())
+	for i, c := range pal {
+		r, g, b, _ := c.RGBA()
+		fmt.Fprintf(w, "#%d;2;%d;%d;%d", i, r*100/0xffff, g*100/0xffff, b*100/0xffff)
+	}
+	row := make([]byte, b.Dx())
+	for y := 0; y < b.Dy(); y += 6 {
+		first := true
+		for ci := range pal {
+			used := false
+			for x := range b.Dx() {
+				var bits byte
+				for k := range min(6, b.Dy()-y) {
+					if idx[(y+k)*b.Dx()+x] == uint8(ci) {
+						bits |= 1 << k
+					}
+				}
+				row[x] = 63 + bits
+				used = used || bits != 0
+			}
+			if !used {
+				continue
+			}
+			if !first {
+				w.WriteByte('$') // carriage return to draw the next colour.
+			}
+			first = false
+			fmt.Fprintf(w, "#%d", ci)
+			writeSixelRLE(w, row)
+		}
+		w.WriteByte('-')
+	}
+	w.WriteString("\x1b\\")
+}
+
+// writeSixelRLE writes the sixels, compressing the runs of the same sixel.
+func writeSixelRLE(w *bufio.Writer, row []byte) {
+	for i := 0; i < len(row); {
+		n := 1
+		for i+n < len(row) && row[i+n] == row[i] {
+			n++
+		}
+		if n > 3 {
+			fmt.Fprintf(w, "!%d%c", n, row[i])
+		} else {
+			for range n {
+				w.WriteByte(row[i])
+			}
+		}
+		i += n
+	}
+}
+
+// imagePalette returns the palette of the distinct colours of the image in
+// the order of appearance, or the web-safe palette, if there are more than
+// 256 colours.
+func imagePalette(img image.Image) color.Palette {
+	var (
+		b    = img.Bounds()
+		pal  color.Palette
+		seen = make(map[color.RGBA]bool)
+	)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := rgbaAt(img, x, y)
+			if seen[c] {
+				continue
+			}
+			if len(pal) == 256 {
+				return palette.WebSafe
+			}
+			seen[c] = true
+			pal = append(pal, c)
+		}
+	}
+	return pal
+}
@@ -0,0 +1,96 @@
+package fontpic
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"testing"
+)
+
+// testImage returns the image from the rows of pixels, where '#' is white
+// and '.' is black.
+func testImage(rows ...string) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, len(rows[0]), len(rows)))
+	for y, row := range rows {
+		for x, c := range row {
+			if c == '#' {
+				img.Set(x, y, color.White)
+			} else {
+				img.Set(x, y, color.Black)
+			}
+		}
+	}
+	return img
+}
+
+func TestWriteTerm(t *testing.T) {
+	tests := []struct {
+		name string
+		img  image.Image
+		mode TermMode
+		want string
+	}{
+		{
+			"halfblock",
+			testImage("#.", ".."),
+			TermHalfBlock,
+			"\x1b[38;2;255;255;255m\x1b[48;2;0;0;0m▀\x1b[38;2;0;0;0m▀\x1b[0m\n",
+		},
+		{
+			"halfblock odd height",
+			testImage("#", ".", "#"),
+			TermHalfBlock,
+			"\x1b[38;2;255;255;255m\x1b[48;2;0;0;0m▀\x1b[0m\n" +
+				"\x1b[38;2;255;255;255m▀\x1b[0m\n",
+		},
+		{
+			"braille",
+			testImage(
+				".#.",
+				"#..",
+				"..#",
+				".##",
+			),
+			TermBraille,
+			"\u288a\u2844\n",
+		},
+		{
+			"braille partial cell",
+			testImage("..", ".#"),
+			TermBraille,
+			"\u2810\n",
+		},
+		{
+			"sixel",
+			testImage(
+				"#.....",
+				"#.....",
+			),
+			TermSixel,
+			"\x1bPq\"1;1;6;2#0;2;100;100;100#1;2;0;0;0#0B!5?$#1?!5B-\x1b\\",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteTerm(&buf, tt.img, tt.mode); err != nil {
+				t.Fatalf("WriteTerm() error = %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("WriteTerm() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTermMode(t *testing.T) {
+	for _, m := range []TermMode{TermHalfBlock, TermBraille, TermSixel} {
+		got, err := ParseTermMode(m.String())
+		if err != nil || got != m {
+			t.Errorf("ParseTermMode(%q) = %v, %v", m, got, err)
+		}
+	}
+	if _, err := ParseTermMode("ascii"); err == nil {
+		t.Error("ParseTermMode(ascii) expected error")
+	}
+}
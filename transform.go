@@ -0,0 +1,60 @@
+package fontpic
+
+// transform.go contains the rotation and mirroring transformations of the
+// fonts.
+
+// Rotate90 returns the copy of the font with the characters rotated 90
+// degrees clockwise.  The width and the height of the font are swapped.  The
+// rotated characters have no horizontal metrics, so the result is always
+// monospaced and without kerning.
+func (f *FNT) Rotate90() *FNT {
+	nf := f.derive(f.Height, f.Width, func(ch byte, x, y int) bool {
+		return f.Pixel(ch, y, f.Height-1-x)
+	})
+	nf.Metrics, nf.Kerning = nil, nil
+	return nf
+}
+
+// Rotate270 returns the copy of the font with the characters rotated 90
+// degrees counterclockwise.  See [FNT.Rotate90].
+func (f *FNT) Rotate270() *FNT {
+	nf := f.derive(f.Height, f.Width, func(ch byte, x, y int) bool {
+		return f.Pixel(ch, f.Width-1-y, x)
+	})
+	nf.Metrics, nf.Kerning = nil, nil
+	return nf
+}
+
+// Rotate180 returns the copy of the font with the characters turned upside
+// down.  It is the same as FlipH and FlipV applied together.
+func (f *FNT) Rotate180() *FNT {
+	return f.FlipH().FlipV()
+}
+
+// FlipH returns the copy of the font with the characters mirrored
+// horizontally.  The metrics of the proportional fonts are mirrored as well,
+// and the kerning pairs are swapped, so that the kerning applies to the text
+// written in the reverse order, as in the mirror writing.
+func (f *FNT) FlipH() *FNT {
+	nf := f.derive(f.Width, f.Height, func(ch byte, x, y int) bool {
+		return f.Pixel(ch, f.Width-1-x, y)
+	})
+	for i, m := range nf.Metrics {
+		nf.Metrics[i].Left = f.Width - m.Left - m.Advance
+	}
+	if f.Kerning != nil {
+		nf.Kerning = make(Kerning, len(f.Kerning))
+		for p, v := range f.Kerning {
+			nf.Kerning[KernPair{p[1], p[0]}] = v
+		}
+	}
+	return nf
+}
+
+// FlipV returns the copy of the font with the characters mirrored
+// vertically.
+func (f *FNT) FlipV() *FNT {
+	return f.derive(f.Width, f.Height, func(ch byte, x, y int) bool {
+		return f.Pixel(ch, x, f.Height-1-y)
+	})
+}
@@ -0,0 +1,81 @@
+package fontpic
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// lFont returns a 3x2 font with the character 'L':
+//
+//	#..
+//	###
+func lFont() *FNT {
+	f := &FNT{Width: 3, Height: 2}
+	f.Chars = toChars(make([]byte, CharsetSz*2), 3, 2)
+	for _, pt := range []image.Point{{0, 0}, {0, 1}, {1, 1}, {2, 1}} {
+		f.SetPixel('L', pt.X, pt.Y, true)
+	}
+	return f
+}
+
+func TestFNT_transforms(t *testing.T) {
+	tests := []struct {
+		name string
+		fnt  *FNT
+		want string
+	}{
+		{"original", lFont(), "#..\n###\n"},
+		{"rotate 90", lFont().Rotate90(), "##\n#.\n#.\n"},
+		{"rotate 180", lFont().Rotate180(), "###\n..#\n"},
+		{"rotate 270", lFont().Rotate270(), ".#\n.#\n##\n"},
+		{"flip h", lFont().FlipH(), "..#\n###\n"},
+		{"flip v", lFont().FlipV(), "###\n#..\n"},
+		{"full turn", lFont().Rotate90().Rotate90().Rotate90().Rotate90(), "#..\n###\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := glyphString(tt.fnt, 'L'); got != tt.want {
+				t.Errorf("glyph =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFNT_FlipH_metrics(t *testing.T) {
+	f := testFont().Proportional(2, 1).WithKerning(Kerning{{'A', 'V'}: -1}).FlipH()
+	// the bar in the column 1 is mirrored to the column 2.
+	if got, want := f.Metrics['I'], (GlyphMetrics{Left: 1, Advance: 2}); got != want {
+		t.Errorf("metrics = %+v, want %+v", got, want)
+	}
+	if got := f.Kern('V', 'A'); got != -1 {
+		t.Errorf("Kern(V, A) = %d, want -1", got)
+	}
+	if got := f.Kern('A', 'V'); got != 0 {
+		t.Errorf("Kern(A, V) = %d, want 0", got)
+	}
+	if r := testFont().Proportional(2, 1).Rotate90(); r.Metrics != nil {
+		t.Error("rotated font has metrics")
+	}
+}
+
+func TestCanvas_Vertical(t *testing.T) {
+	img := NewCanvas(testFont()).
+		WithForeground(color.White).
+		WithVertical(true).
+		RenderText([]byte("II\nI")).
+		Image()
+	if got, want := img.Bounds().Size(), image.Pt(8, 8); got != want {
+		t.Fatalf("size = %v, want %v", got, want)
+	}
+	// the first column has two bars at x=1, the second column has one at
+	// x=5.
+	for y := range 8 {
+		for x := range 8 {
+			want := x == 1 || (x == 5 && y < 4)
+			if got := img.At(x, y) == (color.RGBA{0xff, 0xff, 0xff, 0xff}); got != want {
+				t.Errorf("pixel %d,%d = %v, want %v", x, y, got, want)
+			}
+		}
+	}
+}
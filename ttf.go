@@ -0,0 +1,730 @@
+package fontpic
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/bits"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/rusq/fontpic/charset"
+)
+
+// ttf.go contains the exporter of the bitmap fonts to the scalable TrueType
+// fonts, where each pixel is a square.
+
+// ttfPixel is the size of the pixel in font units.
+const ttfPixel = 64
+
+// TTFOptions are the options for [FNT.WriteTTF].
+type TTFOptions struct {
+	Family    string // Font family name, i.e. "KeyRus 8x16".
+	Style     string // Style name, "Regular" if empty.
+	Version   string // Version string, "Version 1.0" if empty.
+	Copyright string // Copyright notice, optional.
+}
+
+// WriteTTF writes the font as a TrueType font.  Each glyph is the outline of
+// the merged pixel squares, the pixel being 64 font units.  The character
+// codes are mapped to the runes with the font charset.  The advances of the
+// proportional fonts and the kerning are preserved.  The font looks crisp at
+// the sizes that are multiples of the font height in pixels.
+func (f *FNT) WriteTTF(w io.Writer, opts TTFOptions) error {
+	if opts.Family == "" {
+		return errors.New("font family name is required")
+	}
+	if opts.Style == "" {
+		opts.Style = "Regular"
+	}
+	if opts.Version == "" {
+		opts.Version = "Version 1.0"
+	}
+	t := newTTFBuilder(f)
+	tables := map[string][]byte{
+		"cmap": t.cmap(),
+		"glyf": t.glyf,
+		"head": t.head(opts),
+		"hhea": t.hhea(),
+		"hmtx": t.hmtx(),
+		"loca": t.loca(),
+		"maxp": t.maxp(),
+		"name": t.name(opts),
+		"OS/2": t.os2(opts),
+		"post": t.post(),
+	}
+	if len(f.Kerning) > 0 {
+		tables["kern"] = t.kern()
+	}
+	_, err := w.Write(assembleSFNT(tables))
+	return err
+}
+
+// ttfGlyph is the TrueType glyph outline.
+type ttfGlyph struct {
+	contours [][]ttfPoint
+	advance  int
+	bounds   [4]int16 // xMin, yMin, xMax, yMax
+}
+
+type ttfPoint struct{ x, y int }
+
+// ttfBuilder converts the font to the TrueType tables.
+type ttfBuilder struct {
+	fnt     *FNT
+	ascent  int // in font units
+	descent int // in font units, positive
+	glyphs  []ttfGlyph
+	glyf    []byte
+	offsets []uint32 // glyph offsets in glyf, len(glyphs)+1
+}
+
+func newTTFBuilder(f *FNT) *ttfBuilder {
+	t := &ttfBuilder{
+		fnt:     f,
+		ascent:  (f.Height - f.descent()) * ttfPixel,
+		descent: f.descent() * ttfPixel,
+	}
+	// glyph 0 is .notdef, followed by the characters of the font.
+	t.glyphs = append(t.glyphs, ttfGlyph{advance: f.Width * ttfPixel})
+	for ch := range CharsetSz {
+		t.glyphs = append(t.glyphs, t.outline(byte(ch)))
+	}
+	var buf bytes.Buffer
+	for _, g := range t.glyphs {
+		t.offsets = append(t.offsets, uint32(buf.Len()))
+		buf.Write(g.encode())
+		for buf.Len()%4 != 0 {
+			buf.WriteByte(0)
+		}
+	}
+	t.offsets = append(t.offsets, uint32(buf.Len()))
+	t.glyf = buf.Bytes()
+	return t
+}
+
+// outline traces the outline of the pixels of the character.  Each pixel
+// contributes four clockwise edges, and the edges shared by the adjacent
+// pixels cancel out, leaving only the boundary, which is then joined into the
+// contours.
+func (t *ttfBuilder) outline(ch byte) ttfGlyph {
+	type edge struct{ from, to ttfPoint }
+	var (
+		f     = t.fnt
+		edges = make(map[edge]bool)
+		order []edge // to keep the output deterministic
+	)
+	add := func(a, b ttfPoint) {
+		if edges[edge{b, a}] {
+			delete(edges, edge{b, a})
+			return
+		}
+		edges[edge{a, b}] = true
+		order = append(order, edge{a, b})
+	}
+	for y := range f.Height {
+		for x := range f.Width {
+			if !f.Pixel(ch, x, y) {
+				continue
+			}
+			// font units, y axis pointing up.
+			x0, x1 := x*ttfPixel, (x+1)*ttfPixel
+			y1 := t.ascent - y*ttfPixel
+			y0 := y1 - ttfPixel
+			add(ttfPoint{x0, y0}, ttfPoint{x0, y1})
+			add(ttfPoint{x0, y1}, ttfPoint{x1, y1})
+			add(ttfPoint{x1, y1}, ttfPoint{x1, y0})
+			add(ttfPoint{x1, y0}, ttfPoint{x0, y0})
+		}
+	}
+	next := make(map[ttfPoint][]edge)
+	for _, e := range order {
+		if edges[e] {
+			next[e.from] = append(next[e.from], e)
+		}
+	}
+	g := ttfGlyph{advance: f.Advance(ch) * ttfPixel}
+	left, _ := f.hmetrics(ch)
+	for _, start := range order {
+		if !edges[start] {
+			continue
+		}
+		var contour []ttfPoint
+		for e := start; edges[e]; {
+			delete(edges, e)
+			contour = append(contour, e.from)
+			for _, n := range next[e.to] {
+				if edges[n] {
+					e = n
+					break
+				}
+			}
+		}
+		g.contours = append(g.contours, simplify(contour, left*ttfPixel))
+	}
+	g.bounds = contourBounds(g.contours)
+	return g
+}
+
+// simplify removes the points that lie on the straight line between their
+// neighbours, and shifts the points left by dx.
+func simplify(c []ttfPoint, dx int) []ttfPoint {
+	var ret []ttfPoint
+	for i, p := range c {
+		prev, next := c[(i+len(c)-1)%len(c)], c[(i+1)%len(c)]
+		if (prev.x == p.x && p.x == next.x) || (prev.y == p.y && p.y == next.y) {
+			continue
+		}
+		ret = append(ret, ttfPoint{p.x - dx, p.y})
+	}
+	return ret
+}
+
+func contourBounds(contours [][]ttfPoint) (b [4]int16) {
+	first := true
+	for _, c := range contours {
+		for _, p := range c {
+			if first {
+				b = [4]int16{int16(p.x), int16(p.y), int16(p.x), int16(p.y)}
+				first = false
+				continue
+			}
+			b[0], b[1] = min(b[0], int16(p.x)), min(b[1], int16(p.y))
+			b[2], b[3] = max(b[2], int16(p.x)), max(b[3], int16(p.y))
+		}
+	}
+	return b
+}
+
+func (g ttfGlyph) numPoints() int {
+	var n int
+	for _, c := range g.contours {
+		n += len(c)
+	}
+	return n
+}
+
+// encode returns the simple glyph data.  Empty glyphs have no data.
+func (g ttfGlyph) encode() []byte {
+	if len(g.contours) == 0 {
+		return nil
+	}
+	var b ttfBuf
+	b.i16(int16(len(g.contours)))
+	for _, v := range g.bounds {
+		b.i16(v)
+	}
+	var end int
+	for _, c := range g.contours {
+		end += len(c)
+		b.u16(uint16(end - 1))
+	}
+	b.u16(0) // no instructions
+	const onCurve = 0x01
+	for range g.numPoints() {
+		b.u8(onCurve)
+	}
+	var prev ttfPoint
+	for _, c := range g.contours {
+		for _, p := range c {
+			b.i16(int16(p.x - prev.x))
+			prev.x = p.x
+		}
+	}
+	for _, c := range g.contours {
+		for _, p := range c {
+			b.i16(int16(p.y - prev.y))
+			prev.y = p.y
+		}
+	}
+	return b.Bytes()
+}
+
+// runes returns the map of the runes to the glyph indexes.
+func (t *ttfBuilder) runes() map[rune]uint16 {
+	cs, ok := charset.ByName(t.fnt.Charset)
+	ret := make(map[rune]uint16)
+	for ch := CharsetSz - 1; ch >= 0; ch-- {
+		r := rune(ch)
+		if ok {
+			r = cs.Rune(byte(ch))
+		}
+		if r == utf8.RuneError || unicode.IsControl(r) {
+			continue
+		}
+		ret[r] = uint16(ch + 1)
+	}
+	return ret
+}
+
+// cmap returns the cmap table with the single format 4 subtable.
+func (t *ttfBuilder) cmap() []byte {
+	runes := t.runes()
+	keys := make([]rune, 0, len(runes))
+	for r := range runes {
+		keys = append(keys, r)
+	}
+	slices.Sort(keys)
+
+	// segments of the consecutive runes mapped to the consecutive glyphs.
+	type segment struct {
+		start, end rune
+		delta      int
+	}
+	var segs []segment
+	for _, r := range keys {
+		delta := int(runes[r]) - int(r)
+		if n := len(segs); n > 0 && segs[n-1].end == r-1 && segs[n-1].delta == delta {
+			segs[n-1].end = r
+			continue
+		}
+		segs = append(segs, segment{r, r, delta})
+	}
+	segs = append(segs, segment{0xffff, 0xffff, 1})
+
+	var sub ttfBuf
+	segX2 := len(segs) * 2
+	searchRange, entrySelector, rangeShift := searchParams(len(segs), 2)
+	sub.u16(4) // format
+	sub.u16(uint16(16 + 4*segX2))
+	sub.u16(0) // language
+	sub.u16(uint16(segX2))
+	sub.u16(searchRange)
+	sub.u16(entrySelector)
+	sub.u16(rangeShift)
+	for _, s := range segs {
+		sub.u16(uint16(s.end))
+	}
+	sub.u16(0) // reserved pad
+	for _, s := range segs {
+		sub.u16(uint16(s.start))
+	}
+	for _, s := range segs {
+		sub.u16(uint16(s.delta))
+	}
+	for range segs {
+		sub.u16(0) // idRangeOffset
+	}
+
+	var b ttfBuf
+	b.u16(0) // version
+	b.u16(2) // number of encoding records
+	// Unicode BMP and Windows Unicode BMP share the subtable.
+	b.u16(0)
+	b.u16(3)
+	b.u32(4 + 8*2)
+	b.u16(3)
+	b.u16(1)
+	b.u32(4 + 8*2)
+	b.Write(sub.Bytes())
+	return b.Bytes()
+}
+
+func (t *ttfBuilder) bounds() (b [4]int16) {
+	first := true
+	for _, g := range t.glyphs {
+		if len(g.contours) == 0 {
+			continue
+		}
+		if first {
+			b, first = g.bounds, false
+			continue
+		}
+		b[0], b[1] = min(b[0], g.bounds[0]), min(b[1], g.bounds[1])
+		b[2], b[3] = max(b[2], g.bounds[2]), max(b[3], g.bounds[3])
+	}
+	return b
+}
+
+func (t *ttfBuilder) head(opts TTFOptions) []byte {
+	var (
+		bold, italic = ttfStyle(opts.Style)
+		macStyle     uint16
+	)
+	if bold {
+		macStyle |= 1 << 0
+	}
+	if italic {
+		macStyle |= 1 << 1
+	}
+	var b ttfBuf
+	b.u32(0x00010000) // version
+	b.u32(0x00010000) // font revision
+	b.u32(0)          // checksum adjustment, set in assembleSFNT
+	b.u32(0x5f0f3cf5) // magic
+	b.u16(0x000b)     // flags: baseline at y=0, lsb at x=0, integer ppem
+	b.u16(uint16(t.fnt.Height * ttfPixel))
+	b.u64(0) // created
+	b.u64(0) // modified
+	for _, v := range t.bounds() {
+		b.i16(v)
+	}
+	b.u16(macStyle)
+	b.u16(uint16(t.fnt.Height)) // lowest recommended ppem
+	b.i16(2)                    // font direction hint
+	b.i16(1)                    // long loca offsets
+	b.i16(0)                    // glyph data format
+	return b.Bytes()
+}
+
+func (t *ttfBuilder) hhea() []byte {
+	var (
+		maxAdv, maxExtent int
+		minLSB, minRSB    = 1 << 15, 1 << 15
+	)
+	for _, g := range t.glyphs {
+		maxAdv = max(maxAdv, g.advance)
+		if len(g.contours) == 0 {
+			continue
+		}
+		minLSB = min(minLSB, int(g.bounds[0]))
+		minRSB = min(minRSB, g.advance-int(g.bounds[2]))
+		maxExtent = max(maxExtent, int(g.bounds[2]))
+	}
+	var b ttfBuf
+	b.u32(0x00010000)
+	b.i16(int16(t.ascent))
+	b.i16(int16(-t.descent))
+	b.i16(0) // line gap
+	b.u16(uint16(maxAdv))
+	b.i16(int16(minLSB))
+	b.i16(int16(minRSB))
+	b.i16(int16(maxExtent))
+	b.i16(1) // caret slope rise
+	b.i16(0) // caret slope run
+	b.i16(0) // caret offset
+	b.u64(0) // reserved
+	b.i16(0) // metric data format
+	b.u16(uint16(len(t.glyphs)))
+	return b.Bytes()
+}
+
+func (t *ttfBuilder) hmtx() []byte {
+	var b ttfBuf
+	for _, g := range t.glyphs {
+		b.u16(uint16(g.advance))
+		b.i16(g.bounds[0])
+	}
+	return b.Bytes()
+}
+
+func (t *ttfBuilder) loca() []byte {
+	var b ttfBuf
+	for _, o := range t.offsets {
+		b.u32(o)
+	}
+	return b.Bytes()
+}
+
+func (t *ttfBuilder) maxp() []byte {
+	var maxPoints, maxContours int
+	for _, g := range t.glyphs {
+		maxPoints = max(maxPoints, g.numPoints())
+		maxContours = max(maxContours, len(g.contours))
+	}
+	var b ttfBuf
+	b.u32(0x00010000)
+	b.u16(uint16(len(t.glyphs)))
+	b.u16(uint16(maxPoints))
+	b.u16(uint16(maxContours))
+	b.u16(0) // max composite points
+	b.u16(0) // max composite contours
+	b.u16(2) // max zones
+	for range 8 {
+		b.u16(0) // twilight points, storage, function defs, etc.
+	}
+	return b.Bytes()
+}
+
+func (t *ttfBuilder) name(opts TTFOptions) []byte {
+	full := opts.Family
+	if opts.Style != "Regular" {
+		full += " " + opts.Style
+	}
+	psName := make([]rune, 0, len(full))
+	for _, r := range full {
+		if r < 0x7f && r > 0x20 && r != '[' && r != ']' && r != '(' && r != ')' && r != '/' && r != '%' {
+			psName = append(psName, r)
+		}
+	}
+	records := []struct {
+		id    uint16
+		value string
+	}{
+		{0, opts.Copyright},
+		{1, opts.Family},
+		{2, opts.Style},
+		{3, full + "; " + opts.Version},
+		{4, full},
+		{5, opts.Version},
+		{6, string(psName)},
+	}
+	var (
+		b     ttfBuf
+		strs  ttfBuf
+		count int
+	)
+	for _, r := range records {
+		if r.value != "" {
+			count++
+		}
+	}
+	b.u16(0) // format
+	b.u16(uint16(count))
+	b.u16(uint16(6 + 12*count))
+	for _, r := range records {
+		if r.value == "" {
+			continue
+		}
+		s := utf16.Encode([]rune(r.value))
+		b.u16(3)      // platform: Windows
+		b.u16(1)      // encoding: Unicode BMP
+		b.u16(0x0409) // language: en-US
+		b.u16(r.id)
+		b.u16(uint16(len(s) * 2))
+		b.u16(uint16(strs.Len()))
+		for _, c := range s {
+			strs.u16(c)
+		}
+	}
+	b.Write(strs.Bytes())
+	return b.Bytes()
+}
+
+// ttfUnicodeRanges are the OS/2 unicode range bits of the blocks, that are
+// covered by the code pages of the fonts.
+var ttfUnicodeRanges = []struct {
+	bit    int
+	lo, hi rune
+}{
+	{0, 0x0000, 0x007f},  // Basic Latin
+	{1, 0x0080, 0x00ff},  // Latin-1 Supplement
+	{9, 0x0400, 0x052f},  // Cyrillic
+	{46, 0x2500, 0x257f}, // Box Drawing
+	{47, 0x2580, 0x259f}, // Block Elements
+}
+
+// ttfStyle returns the bold and italic flags of the style name, i.e.
+// "Bold Italic".
+func ttfStyle(style string) (bold, italic bool) {
+	for _, w := range strings.Fields(strings.ToLower(style)) {
+		switch w {
+		case "bold":
+			bold = true
+		case "italic", "oblique":
+			italic = true
+		}
+	}
+	return bold, italic
+}
+
+func (t *ttfBuilder) os2(opts TTFOptions) []byte {
+	var (
+		f          = t.fnt
+		sumAdv     int
+		runes      = t.runes()
+		first      = rune(0xffff)
+		last       = rune(0)
+		cpr1, cpr2 uint32
+		ranges     [4]uint32
+	)
+	for _, g := range t.glyphs[1:] {
+		sumAdv += g.advance
+	}
+	for r := range runes {
+		first, last = min(first, r), max(last, r)
+		for _, ur := range ttfUnicodeRanges {
+			if ur.lo <= r && r <= ur.hi {
+				ranges[ur.bit/32] |= 1 << (ur.bit % 32)
+			}
+		}
+	}
+	if cs, ok := charset.ByName(f.Charset); ok && cs == charset.CP866 {
+		cpr2 |= 1 << (49 - 32) // IBM Cyrillic, MS-DOS Russian
+	} else {
+		cpr1 |= 1 << 0 // Latin 1
+	}
+	var (
+		weight       = uint16(400)
+		fsSelection  uint16
+		bold, italic = ttfStyle(opts.Style)
+	)
+	if bold {
+		weight = 700
+		fsSelection |= 1 << 5
+	}
+	if italic {
+		fsSelection |= 1 << 0
+	}
+	if !bold && !italic {
+		fsSelection = 1 << 6 // regular
+	}
+	xHeight := (f.Height - f.descent()) * ttfPixel / 2
+	var b ttfBuf
+	b.u16(4)                                   // version
+	b.i16(int16(sumAdv / (len(t.glyphs) - 1))) // average width
+	b.u16(weight)
+	b.u16(5) // width class: medium
+	b.u16(0) // fsType: installable
+	// subscript and superscript sizes and offsets
+	em := int16(f.Height * ttfPixel)
+	b.i16(em / 2)
+	b.i16(em / 2)
+	b.i16(0)
+	b.i16(em / 8)
+	b.i16(em / 2)
+	b.i16(em / 2)
+	b.i16(0)
+	b.i16(em / 4)
+	b.i16(ttfPixel)                                    // strikeout size
+	b.i16(int16(t.ascent - f.strikeoutRow()*ttfPixel)) // strikeout position
+	b.i16(0)                                           // family class
+	b.Write(make([]byte, 10))                          // panose
+	for _, v := range ranges {
+		b.u32(v) // unicode ranges
+	}
+	b.Write([]byte("FPIC"))
+	b.u16(fsSelection)
+	b.u16(uint16(min(first, 0xffff)))
+	b.u16(uint16(min(last, 0xffff)))
+	b.i16(int16(t.ascent))
+	b.i16(int16(-t.descent))
+	b.i16(0) // typo line gap
+	b.u16(uint16(t.ascent))
+	b.u16(uint16(t.descent))
+	b.u32(cpr1)
+	b.u32(cpr2)
+	b.i16(int16(xHeight))
+	b.i16(int16(t.ascent))
+	b.u16(0)   // default char
+	b.u16(' ') // break char
+	b.u16(1)   // max context
+	return b.Bytes()
+}
+
+func (t *ttfBuilder) post() []byte {
+	var b ttfBuf
+	b.u32(0x00030000)
+	b.u32(0) // italic angle
+	b.i16(int16(-t.descent / 2))
+	b.i16(ttfPixel)
+	if len(t.fnt.Metrics) == 0 {
+		b.u32(1) // fixed pitch
+	} else {
+		b.u32(0)
+	}
+	b.u32(0)
+	b.u32(0)
+	b.u32(0)
+	b.u32(0)
+	return b.Bytes()
+}
+
+// kern returns the kern table with a single format 0 subtable.
+func (t *ttfBuilder) kern() []byte {
+	type pair struct {
+		left, right uint16
+		value       int16
+	}
+	var pairs []pair
+	for p, v := range t.fnt.Kerning {
+		pairs = append(pairs, pair{uint16(p[0]) + 1, uint16(p[1]) + 1, int16(v * ttfPixel)})
+	}
+	slices.SortFunc(pairs, func(a, b pair) int {
+		return int(uint32(a.left)<<16|uint32(a.right)) - int(uint32(b.left)<<16|uint32(b.right))
+	})
+	searchRange, entrySelector, rangeShift := searchParams(len(pairs), 6)
+	var b ttfBuf
+	b.u16(0) // version
+	b.u16(1) // number of subtables
+	b.u16(0) // subtable version
+	b.u16(uint16(14 + 6*len(pairs)))
+	b.u16(0x0001) // format 0, horizontal
+	b.u16(uint16(len(pairs)))
+	b.u16(searchRange)
+	b.u16(entrySelector)
+	b.u16(rangeShift)
+	for _, p := range pairs {
+		b.u16(p.left)
+		b.u16(p.right)
+		b.i16(p.value)
+	}
+	return b.Bytes()
+}
+
+// searchParams returns the binary search parameters for n items of the
+// given size.
+func searchParams(n, size int) (searchRange, entrySelector, rangeShift uint16) {
+	if n == 0 {
+		return 0, 0, 0
+	}
+	sel := bits.Len(uint(n)) - 1
+	sr := (1 << sel) * size
+	return uint16(sr), uint16(sel), uint16(n*size - sr)
+}
+
+// assembleSFNT assembles the tables into the font file.
+func assembleSFNT(tables map[string][]byte) []byte {
+	tags := make([]string, 0, len(tables))
+	for tag := range tables {
+		tags = append(tags, tag)
+	}
+	slices.Sort(tags)
+
+	searchRange, entrySelector, rangeShift := searchParams(len(tags), 16)
+	var b ttfBuf
+	b.u32(0x00010000)
+	b.u16(uint16(len(tags)))
+	b.u16(searchRange)
+	b.u16(entrySelector)
+	b.u16(rangeShift)
+	var (
+		offset  = 12 + 16*len(tags)
+		headOff int
+	)
+	for _, tag := range tags {
+		data := tables[tag]
+		if tag == "head" {
+			headOff = offset
+		}
+		b.Write([]byte(tag))
+		b.u32(ttfChecksum(data))
+		b.u32(uint32(offset))
+		b.u32(uint32(len(data)))
+		offset += (len(data) + 3) &^ 3
+	}
+	for _, tag := range tags {
+		b.Write(tables[tag])
+		for b.Len()%4 != 0 {
+			b.WriteByte(0)
+		}
+	}
+	font := b.Bytes()
+	binary.BigEndian.PutUint32(font[headOff+8:], 0xb1b0afba-ttfChecksum(font))
+	return font
+}
+
+func ttfChecksum(data []byte) uint32 {
+	var sum uint32
+	for i := 0; i < len(data); i += 4 {
+		var word [4]byte
+		copy(word[:], data[i:])
+		sum += binary.BigEndian.Uint32(word[:])
+	}
+	return sum
+}
+
+// ttfBuf is a buffer with big-endian writers.
+type ttfBuf struct {
+	bytes.Buffer
+}
+
+func (b *ttfBuf) u8(v uint8)   { b.WriteByte(v) }
+func (b *ttfBuf) u16(v uint16) { b.Write(binary.BigEndian.AppendUint16(nil, v)) }
+func (b *ttfBuf) i16(v int16)  { b.u16(uint16(v)) }
+func (b *ttfBuf) u32(v uint32) { b.Write(binary.BigEndian.AppendUint32(nil, v)) }
+func (b *ttfBuf) u64(v uint64) { b.Write(binary.BigEndian.AppendUint64(nil, v)) }
@@ -0,0 +1,147 @@
+package fontpic
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+func TestFNT_WriteTTF(t *testing.T) {
+	tests := []struct {
+		name     string
+		fnt      *FNT
+		opts     TTFOptions
+		r        rune
+		wantChar int
+	}{
+		{"keyrus 8x16", Fnt8x16, TTFOptions{Family: "KeyRus 8x16"}, 'Ж', 0x86},
+		{"microfont", IFMicrofont.ToFnt().Proportional(2, 1), TTFOptions{Family: "Microfont"}, 'A', 'A'},
+		{"robotron", Must(LookupFont("robotron")), TTFOptions{Family: "Robotron", Style: "Bold"}, '%', '%'},
+		{"kerning", Fnt8x8.WithKerning(Kerning{{'A', 'V'}: -1}), TTFOptions{Family: "KeyRus 8x8"}, 'V', 'V'},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tt.fnt.WriteTTF(&buf, tt.opts); err != nil {
+				t.Fatal(err)
+			}
+			otf, err := sfnt.Parse(buf.Bytes())
+			if err != nil {
+				t.Fatalf("sfnt.Parse: %v", err)
+			}
+			var sb sfnt.Buffer
+			if n := otf.NumGlyphs(); n != CharsetSz+1 {
+				t.Errorf("NumGlyphs() = %d, want %d", n, CharsetSz+1)
+			}
+			if name, err := otf.Name(&sb, sfnt.NameIDFamily); err != nil || name != tt.opts.Family {
+				t.Errorf("Name() = %q, %v, want %q", name, err, tt.opts.Family)
+			}
+			idx, err := otf.GlyphIndex(&sb, tt.r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if int(idx) != tt.wantChar+1 {
+				t.Errorf("GlyphIndex(%q) = %d, want %d", tt.r, idx, tt.wantChar+1)
+			}
+			ppem := fixed.I(tt.fnt.Height)
+			adv, err := otf.GlyphAdvance(&sb, idx, ppem, font.HintingNone)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := fixed.I(tt.fnt.Advance(byte(tt.wantChar))); adv != want {
+				t.Errorf("GlyphAdvance() = %v, want %v", adv, want)
+			}
+			segs, err := otf.LoadGlyph(&sb, idx, ppem, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(segs) == 0 {
+				t.Error("LoadGlyph() returned no segments")
+			}
+			if len(tt.fnt.Kerning) > 0 {
+				a, _ := otf.GlyphIndex(&sb, 'A')
+				k, err := otf.Kern(&sb, a, idx, ppem, font.HintingNone)
+				if err != nil || k != fixed.I(-1) {
+					t.Errorf("Kern() = %v, %v, want %v", k, err, fixed.I(-1))
+				}
+			}
+		})
+	}
+}
+
+func Test_ttfBuilder_style(t *testing.T) {
+	tests := []struct {
+		name            string
+		fnt             *FNT
+		style           string
+		wantMacStyle    uint16
+		wantSelection   uint16
+		wantUnicodeRng1 uint32
+	}{
+		// CP866 has the degree sign and the like from Latin-1.
+		{"cp866 regular", Fnt8x16, "Regular", 0, 1 << 6, 1<<0 | 1<<1 | 1<<9},
+		// without the charset, the upper half is Latin-1, not Cyrillic.
+		{"no charset bold", IFMicrofont.ToFnt(), "Bold", 1 << 0, 1 << 5, 1<<0 | 1<<1},
+		{"bold italic", Fnt8x8, "Bold Italic", 1<<0 | 1<<1, 1<<0 | 1<<5, 1<<0 | 1<<1 | 1<<9},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				b    = newTTFBuilder(tt.fnt)
+				opts = TTFOptions{Family: "Test", Style: tt.style}
+				head = b.head(opts)
+				os2  = b.os2(opts)
+			)
+			if got := binary.BigEndian.Uint16(head[44:]); got != tt.wantMacStyle {
+				t.Errorf("macStyle = %#x, want %#x", got, tt.wantMacStyle)
+			}
+			if got := binary.BigEndian.Uint16(os2[62:]); got != tt.wantSelection {
+				t.Errorf("fsSelection = %#x, want %#x", got, tt.wantSelection)
+			}
+			if got := binary.BigEndian.Uint32(os2[42:]); got != tt.wantUnicodeRng1 {
+				t.Errorf("ulUnicodeRange1 = %#x, want %#x", got, tt.wantUnicodeRng1)
+			}
+		})
+	}
+}
+
+func TestFNT_WriteTTF_roundtrip(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Fnt8x16.WriteTTF(&buf, TTFOptions{Family: "KeyRus 8x16"}); err != nil {
+		t.Fatal(err)
+	}
+	otf, err := sfnt.Parse(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// at 16 pixels per em, the pixels of the outlines are aligned with the
+	// pixel grid, so the rasterized font must match the original.
+	got, err := Rasterize(otf, RasterOptions{Width: 8, Height: 16, Charset: "866"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for ch := 0x20; ch < CharsetSz; ch++ {
+		if ch == 0x7f {
+			continue
+		}
+		if g, w := glyphString(got, byte(ch)), glyphString(Fnt8x16, byte(ch)); g != w {
+			t.Fatalf("character %#x:\n%s\nwant:\n%s", ch, g, w)
+		}
+	}
+}
+
+func TestFaceToFnt(t *testing.T) {
+	fnt := FaceToFnt(FaceRobotron)
+	if fnt.Width != 9 || fnt.Height != 18 {
+		t.Errorf("size = %dx%d, want 9x18", fnt.Width, fnt.Height)
+	}
+	// 35 is '#': "X.X.X.X.X" in the third row, which is the fifth row after
+	// the blank rows are injected.
+	if g := glyphString(fnt, '#'); g[4*10:5*10] != "#.#.#.#.#\n" {
+		t.Errorf("glyph:\n%s", g)
+	}
+}